@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	_ "github.com/marcelofabianov/fault"
 
 	_ "github.com/marcelofabianov/dojo-go/docs"
-	"github.com/marcelofabianov/dojo-go/internal/di"
+	"github.com/marcelofabianov/dojo-go/internal/cli"
 )
 
 // @title           Dojo Go API
@@ -21,6 +25,19 @@ import (
 
 // @host      localhost:8080
 // @BasePath  /api/v1
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 JWT bearer token, e.g. "Bearer <token>". Required when APP_AUTH_ENABLED=true.
+
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
+// @description                 API key for machine clients, e.g. "dojo_<prefix>_<secret>".
 func main() {
-	di.New().Run()
+	if err := cli.Execute(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
@@ -14,7 +14,9 @@ type Config struct {
 	General GeneralConfig `mapstructure:"general"`
 	Logger  LoggerConfig  `mapstructure:"logger"`
 	Server  ServerConfig  `mapstructure:"server"`
+	Authz   AuthzConfig   `mapstructure:"authz"`
 	DB      DBConfig      `mapstructure:"db"`
+	Tracing TracingConfig `mapstructure:"tracing"`
 }
 
 type GeneralConfig struct {
@@ -26,9 +28,26 @@ type LoggerConfig struct {
 	Level string `mapstructure:"level"`
 }
 
+type AuthzConfig struct {
+	Enabled bool                `mapstructure:"enabled"`
+	Roles   map[string][]string `mapstructure:"roles"`
+}
+
 type ServerConfig struct {
-	API  APIConfig  `mapstructure:"api"`
-	CORS CORSConfig `mapstructure:"cors"`
+	API         APIConfig         `mapstructure:"api"`
+	CORS        CORSConfig        `mapstructure:"cors"`
+	Events      EventsConfig      `mapstructure:"events"`
+	Collab      CollabConfig      `mapstructure:"collab"`
+	GraphQL     GraphQLConfig     `mapstructure:"graphql"`
+	GRPC        GRPCConfig        `mapstructure:"grpc"`
+	Idempotency IdempotencyConfig `mapstructure:"idempotency"`
+	Batch       BatchConfig       `mapstructure:"batch"`
+	Auth        AuthConfig        `mapstructure:"auth"`
+	Tenancy     TenancyConfig     `mapstructure:"tenancy"`
+	Metrics     MetricsConfig     `mapstructure:"metrics"`
+	AccessLog   AccessLogConfig   `mapstructure:"access_log"`
+	Health      HealthConfig      `mapstructure:"health"`
+	Shutdown    ShutdownConfig    `mapstructure:"shutdown"`
 }
 
 type APIConfig struct {
@@ -49,12 +68,107 @@ type CORSConfig struct {
 	AllowCredentials bool     `mapstructure:"allowcredentials"`
 }
 
+type EventsConfig struct {
+	ReplayBufferSize  int           `mapstructure:"replay_buffer_size"`
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
+}
+
+type CollabConfig struct {
+	PersistInterval time.Duration `mapstructure:"persist_interval"`
+	MaxMessageSize  int64         `mapstructure:"max_message_size"`
+}
+
+type GraphQLConfig struct {
+	MaxDepth      int `mapstructure:"max_depth"`
+	MaxComplexity int `mapstructure:"max_complexity"`
+}
+
+type GRPCConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Host    string `mapstructure:"host"`
+	Port    int    `mapstructure:"port"`
+}
+
+type IdempotencyConfig struct {
+	TTL             time.Duration `mapstructure:"ttl"`
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval"`
+}
+
+type BatchConfig struct {
+	MaxOperations int `mapstructure:"max_operations"`
+}
+
+type AuthConfig struct {
+	Enabled             bool          `mapstructure:"enabled"`
+	Issuer              string        `mapstructure:"issuer"`
+	Audience            string        `mapstructure:"audience"`
+	HMACSecret          string        `mapstructure:"hmac_secret" secret:"true"`
+	JWKSFile            string        `mapstructure:"jwks_file"`
+	JWKSURL             string        `mapstructure:"jwks_url"`
+	JWKSRefreshInterval time.Duration `mapstructure:"jwks_refresh_interval"`
+	ClockSkew           time.Duration `mapstructure:"clock_skew"`
+}
+
+type TenancyConfig struct {
+	Header     string `mapstructure:"header"`
+	BaseDomain string `mapstructure:"base_domain"`
+	Default    string `mapstructure:"default"`
+	RateLimit  int    `mapstructure:"rate_limit"`
+}
+
+// MetricsConfig serves the metrics on the API listener when Port is 0, or on
+// a separate admin listener otherwise.
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+	Host    string `mapstructure:"host"`
+	Port    int    `mapstructure:"port"`
+}
+
+// AccessLogConfig samples 2xx responses with SuccessSampleRate (0 to 1);
+// other responses and requests slower than SlowThreshold are always logged.
+type AccessLogConfig struct {
+	Enabled           bool          `mapstructure:"enabled"`
+	SuccessSampleRate float64       `mapstructure:"success_sample_rate"`
+	ExcludePaths      []string      `mapstructure:"exclude_paths"`
+	SlowThreshold     time.Duration `mapstructure:"slow_threshold"`
+}
+
+type HealthConfig struct {
+	CheckTimeout        time.Duration `mapstructure:"check_timeout"`
+	CacheTTL            time.Duration `mapstructure:"cache_ttl"`
+	DiskPath            string        `mapstructure:"disk_path"`
+	MinFreeDiskMB       uint64        `mapstructure:"min_free_disk_mb"`
+	MinMigrationVersion int64         `mapstructure:"min_migration_version"`
+}
+
+// ShutdownConfig bounds the shutdown sequence. DrainDelay is how long the
+// instance keeps serving after failing readiness, so load balancers stop
+// routing to it first; Timeout bounds the whole sequence, drain included.
+type ShutdownConfig struct {
+	DrainDelay time.Duration `mapstructure:"drain_delay"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+}
+
+// TracingConfig selects the span exporter: "otlp" (gRPC), "stdout", "file"
+// or "none". With "none" spans are still created and propagated, so trace ids
+// keep showing up in logs and downstream services.
+type TracingConfig struct {
+	Exporter    string  `mapstructure:"exporter"`
+	ServiceName string  `mapstructure:"service_name"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	FilePath    string  `mapstructure:"file_path"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
 type DBConfig struct {
 	Driver          string        `mapstructure:"driver"`
+	Path            string        `mapstructure:"path"`
 	Host            string        `mapstructure:"host"`
 	Port            int           `mapstructure:"port"`
 	User            string        `mapstructure:"user"`
-	Password        string        `mapstructure:"password"`
+	Password        string        `mapstructure:"password" secret:"true"`
 	Name            string        `mapstructure:"name"`
 	SSLMode         string        `mapstructure:"ssl_mode"`
 	MaxOpenConns    int           `mapstructure:"maxopenconns"`
@@ -63,6 +177,10 @@ type DBConfig struct {
 	ConnMaxIdleTime time.Duration `mapstructure:"connmaxidletime"`
 	QueryTimeout    time.Duration `mapstructure:"querytimeout"`
 	ExecTimeout     time.Duration `mapstructure:"exectimeout"`
+	SlowQuery       time.Duration `mapstructure:"slow_query"`
+	AutoMigrate     bool          `mapstructure:"auto_migrate"`
+	Replicas        []string      `mapstructure:"replicas" secret:"true"`
+	ReplicaCheck    time.Duration `mapstructure:"replica_check"`
 }
 
 func NewConfig() (*Config, error) {
@@ -86,10 +204,54 @@ func NewConfig() (*Config, error) {
 	v.SetDefault("server.api.maxbodysize", 1048576)
 	v.SetDefault("server.cors.allowedorigins", []string{"*"})
 	v.SetDefault("server.cors.allowedmethods", []string{"GET", "POST"})
-	v.SetDefault("server.cors.allowedheaders", []string{"Content-Type", "Authorization"})
-	v.SetDefault("server.cors.exposedheaders", []string{})
+	v.SetDefault("server.cors.allowedheaders", []string{"Content-Type", "Authorization", "Idempotency-Key", "X-API-Key", "X-Tenant-ID", "traceparent", "tracestate"})
+	v.SetDefault("server.cors.exposedheaders", []string{"Idempotent-Replayed"})
 	v.SetDefault("server.cors.allowcredentials", true)
+	v.SetDefault("server.events.replay_buffer_size", 1024)
+	v.SetDefault("server.events.heartbeat_interval", "15s")
+	v.SetDefault("server.collab.persist_interval", "5s")
+	v.SetDefault("server.collab.max_message_size", 7000)
+	v.SetDefault("server.graphql.max_depth", 5)
+	v.SetDefault("server.graphql.max_complexity", 200)
+	v.SetDefault("server.grpc.enabled", true)
+	v.SetDefault("server.grpc.host", "0.0.0.0")
+	v.SetDefault("server.grpc.port", 9090)
+	v.SetDefault("server.idempotency.ttl", "24h")
+	v.SetDefault("server.idempotency.cleanup_interval", "1h")
+	v.SetDefault("server.batch.max_operations", 100)
+	v.SetDefault("server.auth.enabled", false)
+	v.SetDefault("server.auth.jwks_refresh_interval", "15m")
+	v.SetDefault("server.auth.clock_skew", "30s")
+	v.SetDefault("server.tenancy.header", "X-Tenant-ID")
+	v.SetDefault("server.tenancy.default", "default")
+	v.SetDefault("server.tenancy.rate_limit", 1000)
+	v.SetDefault("server.metrics.enabled", true)
+	v.SetDefault("server.metrics.path", "/metrics")
+	v.SetDefault("server.metrics.host", "0.0.0.0")
+	v.SetDefault("server.metrics.port", 0)
+	v.SetDefault("server.access_log.enabled", true)
+	v.SetDefault("server.access_log.success_sample_rate", 1.0)
+	v.SetDefault("server.access_log.exclude_paths", []string{"/livez", "/readyz", "/healthz", "/ping"})
+	v.SetDefault("server.access_log.slow_threshold", "1s")
+	v.SetDefault("server.health.check_timeout", "2s")
+	v.SetDefault("server.health.cache_ttl", "5s")
+	v.SetDefault("server.health.disk_path", "/")
+	v.SetDefault("server.health.min_free_disk_mb", 100)
+	v.SetDefault("server.health.min_migration_version", 0)
+	v.SetDefault("server.shutdown.drain_delay", "5s")
+	v.SetDefault("server.shutdown.timeout", "30s")
+	v.SetDefault("authz.enabled", false)
+	v.SetDefault("authz.roles.viewer", []string{"course:read"})
+	v.SetDefault("authz.roles.editor", []string{"course:read", "course:create", "course:update"})
+	v.SetDefault("authz.roles.admin", []string{"course:read", "course:create", "course:update", "course:delete", "apikey:manage", "audit:read"})
+	v.SetDefault("tracing.exporter", "none")
+	v.SetDefault("tracing.service_name", "dojo-go")
+	v.SetDefault("tracing.endpoint", "localhost:4317")
+	v.SetDefault("tracing.insecure", true)
+	v.SetDefault("tracing.file_path", "traces.jsonl")
+	v.SetDefault("tracing.sample_ratio", 1.0)
 	v.SetDefault("db.driver", "postgres")
+	v.SetDefault("db.path", "dojo.db")
 	v.SetDefault("db.host", "localhost")
 	v.SetDefault("db.port", 5432)
 	v.SetDefault("db.user", "user")
@@ -102,6 +264,10 @@ func NewConfig() (*Config, error) {
 	v.SetDefault("db.connmaxidletime", "10m")
 	v.SetDefault("db.querytimeout", "5s")
 	v.SetDefault("db.exectimeout", "3s")
+	v.SetDefault("db.slow_query", "500ms")
+	v.SetDefault("db.auto_migrate", false)
+	v.SetDefault("db.replicas", []string{})
+	v.SetDefault("db.replica_check", "5s")
 
 	v.SetConfigName(".env")
 	v.SetConfigType("env")
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"reflect"
+	"time"
+)
+
+// Masked replaces the value of every non-empty field tagged secret:"true".
+const Masked = "********"
+
+// Redacted returns cfg as nested maps keyed by the mapstructure names, the
+// same keys used in config files and APP_ variables, with secrets masked and
+// durations rendered as strings.
+func Redacted(cfg *Config) map[string]any {
+	return redactStruct(reflect.ValueOf(cfg).Elem())
+}
+
+func redactStruct(v reflect.Value) map[string]any {
+	out := make(map[string]any, v.NumField())
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		key := field.Tag.Get("mapstructure")
+		if key == "" || !field.IsExported() {
+			continue
+		}
+
+		value := v.Field(i)
+		if field.Tag.Get("secret") == "true" && !isEmpty(value) {
+			out[key] = Masked
+			continue
+		}
+		out[key] = redactValue(value)
+	}
+
+	return out
+}
+
+func isEmpty(v reflect.Value) bool {
+	if v.Kind() == reflect.Slice {
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+func redactValue(v reflect.Value) any {
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+	if v.Kind() == reflect.Struct {
+		return redactStruct(v)
+	}
+	return v.Interface()
+}
@@ -0,0 +1,38 @@
+//go:build unit
+
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcelofabianov/dojo-go/config"
+)
+
+func TestRedacted(t *testing.T) {
+	t.Run("should mask secrets and keep the other values", func(t *testing.T) {
+		cfg := &config.Config{}
+		cfg.DB.Password = "s3cr3t"
+		cfg.DB.Host = "dojo-db"
+		cfg.Server.Auth.HMACSecret = "hmac"
+		cfg.Server.API.ReadTimeout = 5 * time.Second
+
+		redacted := config.Redacted(cfg)
+
+		db := redacted["db"].(map[string]any)
+		assert.Equal(t, config.Masked, db["password"])
+		assert.Equal(t, "dojo-db", db["host"])
+
+		server := redacted["server"].(map[string]any)
+		assert.Equal(t, config.Masked, server["auth"].(map[string]any)["hmac_secret"])
+		assert.Equal(t, "5s", server["api"].(map[string]any)["read_timeout"])
+	})
+
+	t.Run("should not mask secrets that are not set", func(t *testing.T) {
+		redacted := config.Redacted(&config.Config{})
+
+		assert.Equal(t, "", redacted["db"].(map[string]any)["password"])
+	})
+}
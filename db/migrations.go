@@ -0,0 +1,31 @@
+// Package db ships the SQL migrations inside the binary, so deploys no longer
+// need the goose CLI or the db/migrations directory next to the executable.
+package db
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations/*.sql sqlite/migrations/*.sql
+var embedded embed.FS
+
+// Migrations returns the goose migrations rooted at db/migrations.
+func Migrations() fs.FS {
+	return sub("migrations")
+}
+
+// SQLiteMigrations returns the migrations of the sqlite driver, rooted at
+// db/sqlite/migrations. They create the same tables as the Postgres set,
+// minus the course event trigger.
+func SQLiteMigrations() fs.FS {
+	return sub("sqlite/migrations")
+}
+
+func sub(dir string) fs.FS {
+	migrations, err := fs.Sub(embedded, dir)
+	if err != nil {
+		panic(err)
+	}
+	return migrations
+}
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE SEQUENCE IF NOT EXISTS course_event_seq;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_course_change() RETURNS TRIGGER AS $$
+DECLARE
+    course_id UUID;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        course_id := OLD.id;
+    ELSE
+        course_id := NEW.id;
+    END IF;
+
+    PERFORM pg_notify('course_events', json_build_object(
+        'id', nextval('course_event_seq'),
+        'operation', TG_OP,
+        'course_id', course_id,
+        'occurred_at', CURRENT_TIMESTAMP
+    )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER courses_notify_change
+    AFTER INSERT OR UPDATE OR DELETE ON courses
+    FOR EACH ROW EXECUTE FUNCTION notify_course_change();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS courses_notify_change ON courses;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS notify_course_change();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP SEQUENCE IF EXISTS course_event_seq;
+-- +goose StatementEnd
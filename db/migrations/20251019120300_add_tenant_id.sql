@@ -0,0 +1,103 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE courses ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE courses ALTER COLUMN tenant_id DROP DEFAULT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_courses_tenant_id ON courses (tenant_id, created_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE api_keys ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE api_keys ALTER COLUMN tenant_id DROP DEFAULT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_api_keys_tenant_id ON api_keys (tenant_id, created_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE idempotency_keys ALTER COLUMN key TYPE VARCHAR(320);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_course_change() RETURNS TRIGGER AS $$
+DECLARE
+    course_id UUID;
+    tenant_id VARCHAR(63);
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        course_id := OLD.id;
+        tenant_id := OLD.tenant_id;
+    ELSE
+        course_id := NEW.id;
+        tenant_id := NEW.tenant_id;
+    END IF;
+
+    PERFORM pg_notify('course_events', json_build_object(
+        'id', nextval('course_event_seq'),
+        'operation', TG_OP,
+        'course_id', course_id,
+        'tenant_id', tenant_id,
+        'occurred_at', CURRENT_TIMESTAMP
+    )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_course_change() RETURNS TRIGGER AS $$
+DECLARE
+    course_id UUID;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        course_id := OLD.id;
+    ELSE
+        course_id := NEW.id;
+    END IF;
+
+    PERFORM pg_notify('course_events', json_build_object(
+        'id', nextval('course_event_seq'),
+        'operation', TG_OP,
+        'course_id', course_id,
+        'occurred_at', CURRENT_TIMESTAMP
+    )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DELETE FROM idempotency_keys WHERE LENGTH(key) > 255;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE idempotency_keys ALTER COLUMN key TYPE VARCHAR(255);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_api_keys_tenant_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_courses_tenant_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE courses DROP COLUMN IF EXISTS tenant_id;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE course_audit_log (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(63) NOT NULL,
+    course_id UUID NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    before JSONB,
+    after JSONB,
+    changes JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_course_audit_log_course ON course_audit_log (tenant_id, course_id, id DESC);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_course_audit_log_actor ON course_audit_log (tenant_id, actor, id DESC);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_course_audit_log_occurred_at ON course_audit_log (tenant_id, occurred_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'course_audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER course_audit_log_append_only
+    BEFORE UPDATE OR DELETE OR TRUNCATE ON course_audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_log_change();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS course_audit_log;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS reject_audit_log_change();
+-- +goose StatementEnd
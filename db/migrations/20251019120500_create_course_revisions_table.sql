@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE course_revisions (
+    course_id UUID NOT NULL REFERENCES courses (id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    tenant_id VARCHAR(63) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    rollback_of INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (course_id, revision)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO course_revisions (course_id, revision, tenant_id, title, description, actor, created_at)
+SELECT id, 1, tenant_id, title, description, 'system:migration', created_at
+FROM courses;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS course_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+//go:build unit
+
+package db_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	migrations "github.com/marcelofabianov/dojo-go/db"
+)
+
+func TestMigrations(t *testing.T) {
+	sets := map[string]fs.FS{
+		"migrations":        migrations.Migrations(),
+		"sqlite/migrations": migrations.SQLiteMigrations(),
+	}
+
+	for dir, embedded := range sets {
+		t.Run("should embed every sql file in db/"+dir, func(t *testing.T) {
+			onDisk, err := filepath.Glob(dir + "/*.sql")
+			require.NoError(t, err)
+			require.NotEmpty(t, onDisk)
+
+			names, err := fs.Glob(embedded, "*.sql")
+			require.NoError(t, err)
+
+			require.Len(t, names, len(onDisk))
+			for i, path := range onDisk {
+				assert.Equal(t, filepath.Base(path), names[i])
+
+				want, err := os.ReadFile(path)
+				require.NoError(t, err)
+				got, err := fs.ReadFile(embedded, names[i])
+				require.NoError(t, err)
+				assert.Equal(t, want, got)
+			}
+		})
+	}
+}
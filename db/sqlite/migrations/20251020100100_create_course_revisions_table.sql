@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE course_revisions (
+    course_id TEXT NOT NULL REFERENCES courses (id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    tenant_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    rollback_of INTEGER,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (course_id, revision)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS course_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE course_audit_log (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL,
+    course_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    request_id TEXT NOT NULL DEFAULT '',
+    before TEXT,
+    after TEXT,
+    changes TEXT NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMP NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_course_audit_log_course ON course_audit_log (tenant_id, course_id, id DESC);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_course_audit_log_actor ON course_audit_log (tenant_id, actor, id DESC);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_course_audit_log_occurred_at ON course_audit_log (tenant_id, occurred_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER course_audit_log_no_update BEFORE UPDATE ON course_audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'course_audit_log is append-only');
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER course_audit_log_no_delete BEFORE DELETE ON course_audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'course_audit_log is append-only');
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS course_audit_log;
+-- +goose StatementEnd
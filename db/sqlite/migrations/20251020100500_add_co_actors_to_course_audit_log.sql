@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE course_audit_log ADD COLUMN co_actors TEXT NOT NULL DEFAULT '[]';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE course_audit_log DROP COLUMN co_actors;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+package authz
+
+import (
+	"net/http"
+
+	"github.com/marcelofabianov/dojo-go/pkg/auth"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+// PrincipalMiddleware turns the JWT claims set by auth.Middleware into the
+// request principal.
+func PrincipalMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := auth.ClaimsFromContext(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := WithPrincipal(r.Context(), Principal{ID: claims.Subject, Roles: claims.Roles})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequirePermission guards handlers that do not go through CourseService,
+// such as streams served from memory.
+func RequirePermission(policy *Policy, permission Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := policy.Authorize(r.Context(), permission); err != nil {
+				web.GetLogger(r.Context()).Warn("permission denied", "permission", permission, "error", err)
+				web.Error(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
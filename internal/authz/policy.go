@@ -0,0 +1,84 @@
+package authz
+
+import (
+	"context"
+	"slices"
+
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+)
+
+type Permission string
+
+const (
+	CourseRead   Permission = "course:read"
+	CourseCreate Permission = "course:create"
+	CourseUpdate Permission = "course:update"
+	CourseDelete Permission = "course:delete"
+	APIKeyManage Permission = "apikey:manage"
+	AuditRead    Permission = "audit:read"
+)
+
+var permissions = []Permission{CourseRead, CourseCreate, CourseUpdate, CourseDelete, APIKeyManage, AuditRead}
+
+func IsPermission(value string) bool {
+	return slices.Contains(permissions, Permission(value))
+}
+
+// Policy maps roles to the permissions configured in AuthzConfig.Roles.
+type Policy struct {
+	enabled bool
+	roles   map[string][]Permission
+}
+
+func NewPolicy(cfg *config.AuthzConfig) *Policy {
+	roles := make(map[string][]Permission, len(cfg.Roles))
+	for role, permissions := range cfg.Roles {
+		for _, permission := range permissions {
+			roles[role] = append(roles[role], Permission(permission))
+		}
+	}
+
+	return &Policy{
+		enabled: cfg.Enabled,
+		roles:   roles,
+	}
+}
+
+// Authorize returns an Unauthorized fault when ctx carries no principal and
+// a Forbidden fault when none of the principal's roles grants permission.
+// Every check passes while authorization is disabled.
+func (p *Policy) Authorize(ctx context.Context, permission Permission) error {
+	if !p.enabled {
+		return nil
+	}
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return fault.New("authentication required", fault.WithCode(fault.Unauthorized))
+	}
+
+	if p.Allows(principal, permission) {
+		return nil
+	}
+
+	return fault.New("permission denied",
+		fault.WithCode(fault.Forbidden),
+		fault.WithContext("permission", string(permission)),
+	)
+}
+
+func (p *Policy) Allows(principal Principal, permission Permission) bool {
+	if principal.System || slices.Contains(principal.Permissions, permission) {
+		return true
+	}
+
+	for _, role := range principal.Roles {
+		if slices.Contains(p.roles[role], permission) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,69 @@
+package authz
+
+import (
+	"context"
+	"slices"
+)
+
+type contextKey string
+
+const principalCtxKey = contextKey("principal")
+
+// Principal is the identity an operation is performed on behalf of. Users
+// get permissions through their roles, machine clients are granted
+// Permissions directly. System principals represent the application itself
+// (background workers, internal transports) and are granted every permission.
+// A system principal that saves work done by users lists them in OnBehalfOf,
+// in the order they acted.
+type Principal struct {
+	ID          string
+	Roles       []string
+	Permissions []Permission
+	System      bool
+	OnBehalfOf  []string
+}
+
+func SystemPrincipal(id string) Principal {
+	return Principal{ID: id, System: true}
+}
+
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalCtxKey).(Principal)
+	return principal, ok
+}
+
+// Actor names the principal bound to ctx in audit records: the JWT subject,
+// "apikey:<id>" for machine clients, "system:<id>" for the application
+// itself and "anonymous" when the request carries no identity. The
+// application acting on behalf of users is named after the last of them.
+func Actor(ctx context.Context) string {
+	principal, ok := PrincipalFromContext(ctx)
+	switch {
+	case !ok || principal.ID == "":
+		return "anonymous"
+	case principal.System && len(principal.OnBehalfOf) > 0:
+		return principal.OnBehalfOf[len(principal.OnBehalfOf)-1]
+	case principal.System:
+		return "system:" + principal.ID
+	default:
+		return principal.ID
+	}
+}
+
+// CoActors names the other users the principal bound to ctx acts on behalf
+// of, besides the one named by Actor.
+func CoActors(ctx context.Context) []string {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || !principal.System || len(principal.OnBehalfOf) < 2 {
+		return nil
+	}
+	return principal.OnBehalfOf[:len(principal.OnBehalfOf)-1]
+}
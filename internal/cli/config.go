@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/dig"
+	"go.uber.org/fx"
+	"gopkg.in/yaml.v3"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/di"
+)
+
+func newConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the resolved configuration",
+	}
+
+	var format string
+	print := &cobra.Command{
+		Use:   "print",
+		Short: "Print the configuration after defaults, .env and APP_ variables, with secrets masked",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cfg *config.Config
+			app := fx.New(fx.NopLogger, di.Config, fx.Populate(&cfg))
+			if err := app.Err(); err != nil {
+				return dig.RootCause(err)
+			}
+
+			redacted := config.Redacted(cfg)
+			w := cmd.OutOrStdout()
+
+			switch format {
+			case "yaml":
+				enc := yaml.NewEncoder(w)
+				enc.SetIndent(2)
+				return enc.Encode(redacted)
+			case "json":
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				return enc.Encode(redacted)
+			default:
+				return fmt.Errorf("unknown output format %q, use yaml or json", format)
+			}
+		},
+	}
+	print.Flags().StringVarP(&format, "output", "o", "yaml", "output format: yaml or json")
+
+	cmd.AddCommand(print)
+
+	return cmd
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+)
+
+// courseOutput mirrors the course payload of the REST API.
+type courseOutput struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func toCourseOutput(c *model.Course) courseOutput {
+	return courseOutput{
+		ID:          c.ID,
+		Title:       c.Title,
+		Description: c.Description,
+		CreatedAt:   c.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// newCoursesCommand lets operators inspect and remove courses straight from
+// the database, going through CourseService so tenancy, revisions and audit
+// behave as for API requests.
+func newCoursesCommand() *cobra.Command {
+	var tenantID, output string
+
+	cmd := &cobra.Command{
+		Use:   "courses",
+		Short: "List, inspect and delete courses of a tenant",
+	}
+	cmd.PersistentFlags().StringVar(&tenantID, "tenant", "", "tenant to operate on (defaults to server.tenancy.default)")
+	cmd.PersistentFlags().StringVarP(&output, "output", "o", "table", "output format: table or json")
+
+	var limit int
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the newest courses",
+		Args:  cobra.NoArgs,
+		RunE: withCourses(&tenantID, func(ctx context.Context, cmd *cobra.Command, courses port.CourseServicePort, args []string) error {
+			list, err := courses.ListCourses(ctx, limit)
+			if err != nil {
+				return err
+			}
+			return printCourses(cmd.OutOrStdout(), output, list)
+		}),
+	}
+	list.Flags().IntVar(&limit, "limit", 50, "maximum number of courses")
+
+	get := &cobra.Command{
+		Use:   "get <id>",
+		Short: "Show a course",
+		Args:  cobra.ExactArgs(1),
+		RunE: withCourses(&tenantID, func(ctx context.Context, cmd *cobra.Command, courses port.CourseServicePort, args []string) error {
+			course, err := courses.GetCourseByID(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return printCourse(cmd.OutOrStdout(), output, course)
+		}),
+	}
+
+	del := &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Delete a course",
+		Args:  cobra.ExactArgs(1),
+		RunE: withCourses(&tenantID, func(ctx context.Context, cmd *cobra.Command, courses port.CourseServicePort, args []string) error {
+			if err := courses.DeleteCourseByID(ctx, args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "deleted %s\n", args[0])
+			return nil
+		}),
+	}
+
+	cmd.AddCommand(list, get, del)
+
+	return cmd
+}
+
+type coursesFunc func(ctx context.Context, cmd *cobra.Command, courses port.CourseServicePort, args []string) error
+
+func withCourses(tenantID *string, fn coursesFunc) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		var (
+			cfg     *config.ServerConfig
+			courses port.CourseServicePort
+		)
+		stop, err := start(cmd.Context(), &cfg, &courses)
+		if err != nil {
+			return err
+		}
+		defer stop()
+
+		ctx, err := operatorContext(cmd.Context(), tenantOrDefault(*tenantID, cfg))
+		if err != nil {
+			return err
+		}
+
+		return fn(ctx, cmd, courses, args)
+	}
+}
+
+func printCourses(w io.Writer, format string, courses []*model.Course) error {
+	out := make([]courseOutput, len(courses))
+	for i, c := range courses {
+		out[i] = toCourseOutput(c)
+	}
+	return printOutput(w, format, out, out)
+}
+
+func printCourse(w io.Writer, format string, course *model.Course) error {
+	out := toCourseOutput(course)
+	return printOutput(w, format, out, []courseOutput{out})
+}
+
+// printOutput writes payload as JSON, or rows as a table.
+func printOutput(w io.Writer, format string, payload any, rows []courseOutput) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(payload)
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tTITLE\tCREATED AT")
+		for _, c := range rows {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", c.ID, c.Title, c.CreatedAt)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q, use table or json", format)
+	}
+}
@@ -0,0 +1,53 @@
+//go:build unit
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+)
+
+func TestPrintCourses(t *testing.T) {
+	createdAt := time.Date(2025, 10, 19, 12, 0, 0, 0, time.UTC)
+	courses := []*model.Course{
+		{ID: "0199a3b0-7c1e-7a42-9f3d-5b8e2c1d4f60", Title: "Go Fundamentals", CreatedAt: createdAt},
+	}
+
+	t.Run("should print a json array for lists", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, printCourses(&buf, "json", courses))
+
+		var out []courseOutput
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+		require.Len(t, out, 1)
+		assert.Equal(t, "2025-10-19T12:00:00Z", out[0].CreatedAt)
+	})
+
+	t.Run("should print a json object for a single course", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, printCourse(&buf, "json", courses[0]))
+
+		var out courseOutput
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+		assert.Equal(t, courses[0].ID, out.ID)
+	})
+
+	t.Run("should print a table", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, printCourses(&buf, "table", courses))
+
+		assert.Contains(t, buf.String(), "ID")
+		assert.Contains(t, buf.String(), "Go Fundamentals")
+	})
+
+	t.Run("should reject unknown formats", func(t *testing.T) {
+		assert.Error(t, printCourses(&bytes.Buffer{}, "xml", courses))
+	})
+}
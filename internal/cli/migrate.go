@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/marcelofabianov/dojo-go/pkg/migrate"
+)
+
+// newMigrateCommand runs the migrations embedded in the binary, replacing
+// the goose CLI.
+func newMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply or roll back the embedded database migrations",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Apply every pending migration",
+			Args:  cobra.NoArgs,
+			RunE: withMigrator(func(ctx context.Context, cmd *cobra.Command, m *migrate.Migrator, args []string) error {
+				return m.Up(ctx)
+			}),
+		},
+		&cobra.Command{
+			Use:   "down",
+			Short: "Roll back the latest migration",
+			Args:  cobra.NoArgs,
+			RunE: withMigrator(func(ctx context.Context, cmd *cobra.Command, m *migrate.Migrator, args []string) error {
+				return m.Down(ctx)
+			}),
+		},
+		&cobra.Command{
+			Use:   "redo",
+			Short: "Roll back the latest migration and apply it again",
+			Args:  cobra.NoArgs,
+			RunE: withMigrator(func(ctx context.Context, cmd *cobra.Command, m *migrate.Migrator, args []string) error {
+				return m.Redo(ctx)
+			}),
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "List migrations and whether they were applied",
+			Args:  cobra.NoArgs,
+			RunE:  withMigrator(printMigrationStatus),
+		},
+		&cobra.Command{
+			Use:   "to-version <version>",
+			Short: "Migrate up or down to the given version",
+			Args:  cobra.ExactArgs(1),
+			RunE: withMigrator(func(ctx context.Context, cmd *cobra.Command, m *migrate.Migrator, args []string) error {
+				version, err := strconv.ParseInt(args[0], 10, 64)
+				if err != nil || version < 0 {
+					return fmt.Errorf("invalid target version %q", args[0])
+				}
+				return m.To(ctx, version)
+			}),
+		},
+	)
+
+	return cmd
+}
+
+type migratorFunc func(ctx context.Context, cmd *cobra.Command, m *migrate.Migrator, args []string) error
+
+func withMigrator(fn migratorFunc) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		var migrator *migrate.Migrator
+		stop, err := start(ctx, &migrator)
+		if err != nil {
+			return err
+		}
+		defer stop()
+
+		return fn(ctx, cmd, migrator, args)
+	}
+}
+
+func printMigrationStatus(ctx context.Context, cmd *cobra.Command, m *migrate.Migrator, args []string) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tFILE")
+	for _, s := range statuses {
+		appliedAt := "-"
+		if !s.AppliedAt.IsZero() {
+			appliedAt = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Source.Version, s.State, appliedAt, s.Source.Path)
+	}
+	return w.Flush()
+}
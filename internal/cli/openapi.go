@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/marcelofabianov/dojo-go/docs"
+)
+
+func newOpenAPICommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "openapi",
+		Short: "Work with the OpenAPI document generated by swag",
+	}
+
+	var file string
+	dump := &cobra.Command{
+		Use:   "dump",
+		Short: "Write the OpenAPI (Swagger 2.0) document as JSON",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var w io.Writer = cmd.OutOrStdout()
+			if file != "" {
+				f, err := os.Create(file)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+
+			_, err := fmt.Fprintln(w, docs.SwaggerInfo.ReadDoc())
+			return err
+		},
+	}
+	dump.Flags().StringVarP(&file, "file", "f", "", "write to a file instead of stdout")
+
+	cmd.AddCommand(dump)
+
+	return cmd
+}
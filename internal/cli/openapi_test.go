@@ -0,0 +1,42 @@
+//go:build unit
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIDump(t *testing.T) {
+	cmd := newOpenAPICommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"dump"})
+	require.NoError(t, cmd.Execute())
+
+	var doc struct {
+		Paths               map[string]any `json:"paths"`
+		SecurityDefinitions map[string]any `json:"securityDefinitions"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &doc))
+
+	// A route missing here means docs/ was not regenerated with swag init.
+	for _, path := range []string{
+		"/courses",
+		"/courses:batch",
+		"/courses/events",
+		"/courses/{id}/collab",
+		"/courses/{id}/revisions",
+		"/graphql",
+		"/api-keys",
+		"/audit/courses",
+	} {
+		assert.Contains(t, doc.Paths, path)
+	}
+	assert.Contains(t, doc.SecurityDefinitions, "BearerAuth")
+	assert.Contains(t, doc.SecurityDefinitions, "ApiKeyAuth")
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/dig"
+	"go.uber.org/fx"
+
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/di"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+// Principal is the identity of CLI operations, recorded as "system:cli" in
+// audit entries and revisions.
+const Principal = "cli"
+
+func NewRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "api",
+		Short:         "Dojo Go API server and operator tools",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.AddCommand(
+		newServeCommand(),
+		newMigrateCommand(),
+		newSeedCommand(),
+		newCoursesCommand(),
+		newConfigCommand(),
+		newOpenAPICommand(),
+	)
+
+	return root
+}
+
+// Execute runs the command selected by the process arguments.
+func Execute(ctx context.Context) error {
+	return NewRootCommand().ExecuteContext(ctx)
+}
+
+// start builds the command graph from internal/di, fills targets with its
+// dependencies and returns a stop func that closes the database pool.
+func start(ctx context.Context, targets ...any) (func(), error) {
+	app := di.NewCommand(fx.Populate(targets...))
+	if err := app.Err(); err != nil {
+		// fx wraps constructor failures in the whole dependency path; an
+		// operator only needs the failure itself.
+		return nil, dig.RootCause(err)
+	}
+	if err := app.Start(ctx); err != nil {
+		return nil, err
+	}
+
+	return func() { app.Stop(context.WithoutCancel(ctx)) }, nil
+}
+
+// operatorContext binds the CLI principal and the tenant operated on.
+func operatorContext(ctx context.Context, tenantID string) (context.Context, error) {
+	if err := tenant.Validate(tenantID); err != nil {
+		return nil, err
+	}
+
+	ctx = authz.WithPrincipal(ctx, authz.SystemPrincipal(Principal))
+	return tenant.WithID(ctx, tenantID), nil
+}
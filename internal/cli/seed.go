@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+)
+
+// Fixture lists the courses loaded by the seed command. JSON is valid YAML,
+// so the same file format covers both.
+type Fixture struct {
+	Courses []FixtureCourse `yaml:"courses"`
+}
+
+type FixtureCourse struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+}
+
+func LoadFixture(r io.Reader) (*Fixture, error) {
+	var fixture Fixture
+	if err := yaml.NewDecoder(r).Decode(&fixture); err != nil {
+		return nil, fault.Wrap(err, "failed to parse fixture file", fault.WithCode(fault.Invalid))
+	}
+
+	if len(fixture.Courses) == 0 {
+		return nil, fault.New("fixture file has no courses", fault.WithCode(fault.Invalid))
+	}
+
+	return &fixture, nil
+}
+
+func newSeedCommand() *cobra.Command {
+	var file, tenantID string
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Load fixture courses from a YAML or JSON file",
+		Long: `Creates every course of the fixture file through the course service, so
+revisions and audit entries are recorded as for API requests. The fixture
+is loaded in a single transaction: either every course is created or none.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			fixture, err := LoadFixture(f)
+			if err != nil {
+				return err
+			}
+
+			var (
+				cfg        *config.ServerConfig
+				courses    port.CourseServicePort
+				transactor port.TransactorPort
+			)
+			stop, err := start(cmd.Context(), &cfg, &courses, &transactor)
+			if err != nil {
+				return err
+			}
+			defer stop()
+
+			ctx, err := operatorContext(cmd.Context(), tenantOrDefault(tenantID, cfg))
+			if err != nil {
+				return err
+			}
+
+			return transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+				for i, c := range fixture.Courses {
+					course, err := courses.CreateCourse(ctx, model.NewCourseInput{
+						Title:       c.Title,
+						Description: c.Description,
+					})
+					if err != nil {
+						return fault.Wrap(err, "failed to seed course",
+							fault.WithContext("index", i),
+							fault.WithContext("title", c.Title),
+						)
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "created %s %q\n", course.ID, course.Title)
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "fixture file (.yaml, .yml or .json)")
+	cmd.Flags().StringVar(&tenantID, "tenant", "", "tenant to seed (defaults to server.tenancy.default)")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func tenantOrDefault(tenantID string, cfg *config.ServerConfig) string {
+	if tenantID != "" {
+		return tenantID
+	}
+	return cfg.Tenancy.Default
+}
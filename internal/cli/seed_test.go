@@ -0,0 +1,57 @@
+//go:build unit
+
+package cli_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/internal/cli"
+)
+
+func TestLoadFixture(t *testing.T) {
+	t.Run("should load a yaml fixture", func(t *testing.T) {
+		fixture, err := cli.LoadFixture(strings.NewReader(`
+courses:
+  - title: Go Fundamentals
+    description: Types and interfaces.
+`))
+
+		require.NoError(t, err)
+		assert.Equal(t, []cli.FixtureCourse{{Title: "Go Fundamentals", Description: "Types and interfaces."}}, fixture.Courses)
+	})
+
+	t.Run("should load a json fixture", func(t *testing.T) {
+		fixture, err := cli.LoadFixture(strings.NewReader(`{"courses": [{"title": "A", "description": "B"}]}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, []cli.FixtureCourse{{Title: "A", Description: "B"}}, fixture.Courses)
+	})
+
+	t.Run("should load the sample fixture shipped in db/seeds", func(t *testing.T) {
+		f, err := os.Open("../../db/seeds/courses.yaml")
+		require.NoError(t, err)
+		defer f.Close()
+
+		fixture, err := cli.LoadFixture(f)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, fixture.Courses)
+	})
+
+	t.Run("should reject a fixture without courses", func(t *testing.T) {
+		_, err := cli.LoadFixture(strings.NewReader(`courses: []`))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject malformed files", func(t *testing.T) {
+		_, err := cli.LoadFixture(strings.NewReader(`courses: [`))
+
+		assert.Error(t, err)
+	})
+}
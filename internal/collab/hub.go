@@ -0,0 +1,530 @@
+package collab
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+const (
+	clientBufferSize       = 64
+	publishTimeout         = 5 * time.Second
+	defaultPersistInterval = 5 * time.Second
+
+	// notifyPayloadLimit is the largest payload Postgres NOTIFY accepts.
+	notifyPayloadLimit = 7999
+)
+
+// persistPrincipal saves the merged state of a room. Editors are authorized
+// to update the course when they join; each save is made on behalf of the
+// editors whose edits it holds (see room.contributors).
+var persistPrincipal = authz.SystemPrincipal("collab")
+
+type Publisher interface {
+	Publish(ctx context.Context, payload string) error
+}
+
+// Hub keeps one room per course with at least one editor connected to this
+// replica. Operations are never applied directly: they are published through
+// the Publisher and applied when they come back from the shared channel, so
+// every replica applies them in the same order.
+type Hub struct {
+	mu              sync.Mutex
+	replica         string
+	rooms           map[string]*room
+	courseService   port.CourseServicePort
+	publisher       Publisher
+	logger          *slog.Logger
+	persistInterval time.Duration
+	maxValueSize    int
+}
+
+// room mirrors the state of a course being edited. fresh holds the fields
+// known to be current: edited since the room was opened, or received from a
+// replica that edited them. The others come from the database and may miss
+// edits another replica has not persisted yet. contributors holds the
+// actors of the local edits not persisted yet, the latest last.
+type room struct {
+	tenant       string
+	course       CourseState
+	seq          uint64
+	dirty        bool
+	fresh        map[string]bool
+	editors      map[string]Editor
+	clients      map[string]*Client
+	contributors []string
+}
+
+// Client is an editor connected to this replica. actor names the identity
+// that joined, as recorded in audit entries.
+type Client struct {
+	hub      *Hub
+	courseID string
+	editor   Editor
+	actor    string
+	send     chan Message
+	closed   bool
+}
+
+func NewHub(
+	cfg *config.ServerConfig,
+	courseService port.CourseServicePort,
+	publisher Publisher,
+	logger *slog.Logger,
+) *Hub {
+	persistInterval := cfg.Collab.PersistInterval
+	if persistInterval <= 0 {
+		persistInterval = defaultPersistInterval
+	}
+
+	return &Hub{
+		replica:         uuid.NewString(),
+		rooms:           make(map[string]*room),
+		courseService:   courseService,
+		publisher:       publisher,
+		logger:          logger,
+		persistInterval: persistInterval,
+		maxValueSize:    int(cfg.Collab.MaxMessageSize),
+	}
+}
+
+// Join adds an editor to the room of a course of the tenant bound to ctx.
+// Rooms remember their tenant, so an editor of another tenant cannot join an
+// open room without going through the tenant scoped course lookup.
+func (h *Hub) Join(ctx context.Context, courseID, name string) (*Client, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &Client{
+		hub:      h,
+		courseID: courseID,
+		editor:   Editor{ID: uuid.NewString(), Name: name},
+		actor:    authz.Actor(ctx),
+		send:     make(chan Message, clientBufferSize),
+	}
+
+	var course *model.Course
+	for {
+		h.mu.Lock()
+		r, ok := h.rooms[courseID]
+		if !ok && course == nil {
+			// Load the course without holding the lock; the room may have been
+			// created by another editor in the meantime, which is fine.
+			h.mu.Unlock()
+
+			var err error
+			course, err = h.courseService.GetCourseByID(ctx, courseID)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if ok && r.tenant != tenantID {
+			h.mu.Unlock()
+			return nil, model.ErrCourseNotFound
+		}
+
+		created := !ok
+		if created {
+			r = &room{
+				tenant: tenantID,
+				course: CourseState{
+					ID:          course.ID,
+					Title:       course.Title,
+					Description: course.Description,
+				},
+				fresh:   make(map[string]bool),
+				editors: make(map[string]Editor),
+				clients: make(map[string]*Client),
+			}
+			h.rooms[courseID] = r
+		}
+		// The editor itself is added to the editors list when its joined
+		// presence comes back from the channel, like on every other replica.
+		r.clients[client.editor.ID] = client
+		client.send <- r.snapshot(client.editor)
+		h.mu.Unlock()
+
+		if created {
+			h.publish(envelope{Kind: envelopeSync, CourseID: courseID})
+		}
+		h.publish(envelope{
+			Kind:     envelopeMessage,
+			CourseID: courseID,
+			Message:  &Message{Type: MessagePresence, Editor: &client.editor, Status: PresenceJoined},
+		})
+
+		return client, nil
+	}
+}
+
+// HandleNotification is registered as the db.Listener handler for the
+// collaboration channel.
+func (h *Hub) HandleNotification(payload string) {
+	var env envelope
+	if err := json.Unmarshal([]byte(payload), &env); err != nil {
+		h.logger.Error("failed to decode collab notification", "error", err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok := h.rooms[env.CourseID]
+	if !ok {
+		return
+	}
+
+	switch env.Kind {
+	case envelopeSync:
+		if env.Replica == h.replica {
+			return
+		}
+		for _, c := range r.clients {
+			go h.publish(envelope{
+				Kind:     envelopeMessage,
+				CourseID: env.CourseID,
+				Message:  &Message{Type: MessagePresence, Editor: &c.editor, Status: PresenceJoined},
+			})
+		}
+		for _, field := range []string{FieldTitle, FieldDescription} {
+			if !r.fresh[field] {
+				continue
+			}
+			go h.publish(envelope{
+				Kind:     envelopeState,
+				CourseID: env.CourseID,
+				Message:  &Message{Type: MessageEdit, Field: field, Value: r.course.field(field)},
+			})
+		}
+	case envelopeState:
+		if env.Replica == h.replica || env.Message == nil {
+			return
+		}
+		h.catchUp(r, *env.Message)
+	case envelopeMessage:
+		if env.Message == nil || env.Message.Editor == nil {
+			return
+		}
+		h.apply(r, env.Replica == h.replica, env.Actor, *env.Message)
+	}
+}
+
+// catchUp takes the value of a field from a replica that edited it, unless
+// this room already has a current one. The state envelope answers a sync
+// published after the room was opened, so any edit made since then has been
+// applied here too and marked the field fresh. Local editors get a new
+// snapshot.
+func (h *Hub) catchUp(r *room, msg Message) {
+	if r.fresh[msg.Field] || !r.course.set(msg.Field, msg.Value) {
+		return
+	}
+	r.fresh[msg.Field] = true
+	r.seq++
+
+	for _, c := range r.clients {
+		h.deliver(r, c, r.snapshot(c.editor))
+	}
+}
+
+func (h *Hub) apply(r *room, local bool, actor string, msg Message) {
+	switch msg.Type {
+	case MessageEdit:
+		if !r.course.set(msg.Field, msg.Value) {
+			return
+		}
+		r.fresh[msg.Field] = true
+		// Only the replica that received the edit persists it, the others
+		// just mirror the state for their own editors.
+		if local {
+			r.dirty = true
+			r.contribute(actor)
+		}
+	case MessagePresence:
+		switch msg.Status {
+		case PresenceJoined:
+			if _, ok := r.editors[msg.Editor.ID]; ok {
+				return
+			}
+			r.editors[msg.Editor.ID] = *msg.Editor
+		case PresenceLeft:
+			delete(r.editors, msg.Editor.ID)
+		case PresenceFocus:
+		default:
+			return
+		}
+	default:
+		return
+	}
+
+	r.seq++
+	msg.Seq = r.seq
+
+	for _, c := range r.clients {
+		h.deliver(r, c, msg)
+	}
+}
+
+// deliver drops editors that cannot keep up instead of blocking the room;
+// they reconnect and receive a fresh snapshot.
+func (h *Hub) deliver(r *room, c *Client, msg Message) {
+	select {
+	case c.send <- msg:
+	default:
+		h.logger.Warn("dropping slow collab editor", "course_id", c.courseID, "editor_id", c.editor.ID)
+		h.detach(r, c)
+	}
+}
+
+func (h *Hub) detach(r *room, c *Client) {
+	if c.closed {
+		return
+	}
+	c.closed = true
+	close(c.send)
+	delete(r.clients, c.editor.ID)
+	delete(r.editors, c.editor.ID)
+}
+
+// Run persists dirty rooms every persist interval until ctx is canceled, then
+// flushes whatever is still pending.
+func (h *Hub) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.persistInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+			defer cancel()
+			h.persistAll(flushCtx)
+			return
+		case <-ticker.C:
+			h.persistAll(ctx)
+		}
+	}
+}
+
+func (h *Hub) persistAll(ctx context.Context) {
+	h.mu.Lock()
+	ids := make([]string, 0, len(h.rooms))
+	for id, r := range h.rooms {
+		if r.dirty {
+			ids = append(ids, id)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, id := range ids {
+		h.persist(ctx, id)
+	}
+}
+
+func (h *Hub) persist(ctx context.Context, courseID string) {
+	h.mu.Lock()
+	r, ok := h.rooms[courseID]
+	if !ok || !r.dirty {
+		h.mu.Unlock()
+		return
+	}
+	state := r.course
+	contributors := r.contributors
+	ctx = tenant.WithID(ctx, r.tenant)
+	r.dirty = false
+	r.contributors = nil
+	h.mu.Unlock()
+
+	principal := persistPrincipal
+	principal.OnBehalfOf = contributors
+	ctx = authz.WithPrincipal(ctx, principal)
+
+	_, err := h.courseService.UpdateCourse(ctx, courseID, model.UpdateCourseInput{
+		Title:       state.Title,
+		Description: state.Description,
+	})
+	if err == nil {
+		h.logger.Info("collab course state persisted", "course_id", courseID)
+		return
+	}
+
+	h.logger.Warn("failed to persist collab course state", "course_id", courseID, "error", err)
+
+	// Invalid intermediate states (e.g. an empty title while typing) stay
+	// dirty and are retried on the next tick.
+	h.mu.Lock()
+	if r, ok := h.rooms[courseID]; ok {
+		r.dirty = true
+		later := r.contributors
+		r.contributors = contributors
+		for _, actor := range later {
+			r.contribute(actor)
+		}
+	}
+	h.mu.Unlock()
+}
+
+// publish broadcasts env to every replica. Failures are logged and returned,
+// so operations submitted by editors can be reported back to them.
+func (h *Hub) publish(env envelope) error {
+	env.Replica = h.replica
+
+	payload, err := json.Marshal(env)
+	if err != nil {
+		h.logger.Error("failed to encode collab notification", "error", err)
+		return fault.Wrap(err, "failed to encode operation", fault.WithCode(fault.Internal))
+	}
+
+	// Values grow when escaped, so a value under the size limit may still
+	// not fit in a notification.
+	if len(payload) > notifyPayloadLimit {
+		h.logger.Warn("collab notification is too large", "course_id", env.CourseID, "size", len(payload))
+		return fault.New("operation is too large to broadcast",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("size", len(payload)),
+			fault.WithContext("max_size", notifyPayloadLimit),
+		)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	if err := h.publisher.Publish(ctx, string(payload)); err != nil {
+		h.logger.Error("failed to publish collab notification", "course_id", env.CourseID, "error", err)
+		// The cause is logged only: the error is sent back to the editor.
+		return fault.New("failed to broadcast operation, try again", fault.WithCode(fault.InfraError))
+	}
+
+	return nil
+}
+
+// contribute moves actor to the end of the contributors.
+func (r *room) contribute(actor string) {
+	r.contributors = slices.DeleteFunc(r.contributors, func(a string) bool { return a == actor })
+	r.contributors = append(r.contributors, actor)
+}
+
+func (r *room) snapshot(self Editor) Message {
+	course := r.course
+	editors := make([]Editor, 0, len(r.editors))
+	for _, e := range r.editors {
+		editors = append(editors, e)
+	}
+
+	return Message{
+		Type:    MessageSnapshot,
+		Seq:     r.seq,
+		Editor:  &self,
+		Course:  &course,
+		Editors: editors,
+	}
+}
+
+func (c *Client) Editor() Editor {
+	return c.editor
+}
+
+// Messages is closed when the client leaves or is dropped by the hub.
+func (c *Client) Messages() <-chan Message {
+	return c.send
+}
+
+// Submit validates an operation sent by the editor and publishes it to
+// every replica. An operation that could not be published is returned as an
+// error, for the editor to retry.
+func (c *Client) Submit(msg Message) error {
+	switch msg.Type {
+	case MessageEdit:
+		if msg.Field != FieldTitle && msg.Field != FieldDescription {
+			return fault.New("unknown field, must be title or description",
+				fault.WithCode(fault.Invalid),
+				fault.WithContext("field", msg.Field),
+			)
+		}
+		if len(msg.Value) > c.hub.maxValueSize {
+			return fault.New("field value is too large for a single edit",
+				fault.WithCode(fault.Invalid),
+				fault.WithContext("max_size", c.hub.maxValueSize),
+			)
+		}
+	case MessagePresence:
+		if msg.Status != PresenceFocus {
+			return fault.New("only focus presence updates can be sent by editors",
+				fault.WithCode(fault.Invalid),
+			)
+		}
+		if msg.Field != "" && msg.Field != FieldTitle && msg.Field != FieldDescription {
+			return fault.New("unknown field, must be title or description",
+				fault.WithCode(fault.Invalid),
+				fault.WithContext("field", msg.Field),
+			)
+		}
+	default:
+		return fault.New("unsupported message type",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("type", msg.Type),
+		)
+	}
+
+	editor := c.editor
+	return c.hub.publish(envelope{
+		Kind:     envelopeMessage,
+		CourseID: c.courseID,
+		Actor:    c.actor,
+		Message: &Message{
+			Type:   msg.Type,
+			Editor: &editor,
+			Field:  msg.Field,
+			Value:  msg.Value,
+			Status: msg.Status,
+		},
+	})
+}
+
+// Leave removes the editor from its room, announces it to the other editors
+// and persists the room if it was the last local editor.
+func (c *Client) Leave() {
+	h := c.hub
+
+	h.mu.Lock()
+	r, ok := h.rooms[c.courseID]
+	if ok {
+		h.detach(r, c)
+	}
+	last := ok && len(r.clients) == 0
+	h.mu.Unlock()
+
+	h.publish(envelope{
+		Kind:     envelopeMessage,
+		CourseID: c.courseID,
+		Message:  &Message{Type: MessagePresence, Editor: &c.editor, Status: PresenceLeft},
+	})
+
+	if !last {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	h.persist(ctx, c.courseID)
+
+	h.mu.Lock()
+	if r, ok := h.rooms[c.courseID]; ok && len(r.clients) == 0 {
+		delete(h.rooms, c.courseID)
+	}
+	h.mu.Unlock()
+}
@@ -0,0 +1,259 @@
+//go:build unit
+
+package collab_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/collab"
+	"github.com/marcelofabianov/dojo-go/internal/mocks"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/service"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+// loopbackPublisher mimics Postgres NOTIFY by delivering every payload, in
+// publish order, to all hubs sharing the channel.
+type loopbackPublisher struct {
+	mu   sync.Mutex
+	hubs []*collab.Hub
+}
+
+func (p *loopbackPublisher) Publish(_ context.Context, payload string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, h := range p.hubs {
+		h.HandleNotification(payload)
+	}
+	return nil
+}
+
+// failingPublisher stands for a channel that is down.
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(context.Context, string) error {
+	return errors.New("connection refused")
+}
+
+func newHub(repo *mocks.MockCourseRepository, pub *loopbackPublisher) *collab.Hub {
+	return newAuditedHub(repo, mocks.NewAuditRepositoryStub(), pub)
+}
+
+func newAuditedHub(repo *mocks.MockCourseRepository, audit *mocks.MockAuditRepository, pub *loopbackPublisher) *collab.Hub {
+	cfg := &config.ServerConfig{Collab: config.CollabConfig{PersistInterval: time.Hour, MaxMessageSize: 100}}
+	hub := collab.NewHub(cfg, service.NewCourseService(repo, mocks.NewCourseRevisionRepositoryStub(), audit, &mocks.FakeTransactor{}, &mocks.FakeMetrics{}, authz.NewPolicy(&config.AuthzConfig{})), pub, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	pub.hubs = append(pub.hubs, hub)
+	return hub
+}
+
+func next(t *testing.T, c *collab.Client) collab.Message {
+	t.Helper()
+	select {
+	case msg := <-c.Messages():
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for collab message")
+		return collab.Message{}
+	}
+}
+
+func TestHub(t *testing.T) {
+	courseID := "0199a000-0000-7000-8000-000000000001"
+	course := &model.Course{ID: courseID, Title: "Go", Description: "Basics"}
+	ctx := tenant.WithID(context.Background(), "school-a")
+
+	t.Run("should send a snapshot and broadcast presence on join", func(t *testing.T) {
+		repo := new(mocks.MockCourseRepository)
+		repo.On("GetCourseByID", mock.Anything, courseID).Return(course, nil).Once()
+		hub := newHub(repo, &loopbackPublisher{})
+
+		alice, err := hub.Join(ctx, courseID, "alice")
+		require.NoError(t, err)
+
+		snapshot := next(t, alice)
+		assert.Equal(t, collab.MessageSnapshot, snapshot.Type)
+		assert.Equal(t, "Go", snapshot.Course.Title)
+
+		presence := next(t, alice)
+		assert.Equal(t, collab.MessagePresence, presence.Type)
+		assert.Equal(t, collab.PresenceJoined, presence.Status)
+		assert.Equal(t, "alice", presence.Editor.Name)
+	})
+
+	t.Run("should order edits across replicas and persist on the origin replica", func(t *testing.T) {
+		repo := new(mocks.MockCourseRepository)
+		repo.On("GetCourseByID", mock.Anything, courseID).Return(&model.Course{ID: courseID, Title: "Go", Description: "Basics"}, nil)
+		pub := &loopbackPublisher{}
+		replicaA := newHub(repo, pub)
+		replicaB := newHub(repo, pub)
+
+		alice, err := replicaA.Join(ctx, courseID, "alice")
+		require.NoError(t, err)
+		bob, err := replicaB.Join(ctx, courseID, "bob")
+		require.NoError(t, err)
+
+		require.NoError(t, alice.Submit(collab.Message{Type: collab.MessageEdit, Field: collab.FieldTitle, Value: "Go 2"}))
+
+		var aliceEdit, bobEdit collab.Message
+		for aliceEdit.Type != collab.MessageEdit {
+			aliceEdit = next(t, alice)
+		}
+		for bobEdit.Type != collab.MessageEdit {
+			bobEdit = next(t, bob)
+		}
+		assert.Equal(t, "Go 2", aliceEdit.Value)
+		assert.Equal(t, "Go 2", bobEdit.Value)
+		assert.Equal(t, "alice", bobEdit.Editor.Name)
+
+		repo.On("UpdateCourse", mock.MatchedBy(func(ctx context.Context) bool {
+			id, _ := tenant.FromContext(ctx)
+			return id == "school-a"
+		}), mock.MatchedBy(func(c *model.Course) bool {
+			return c.Title == "Go 2"
+		})).Return(nil).Once()
+
+		alice.Leave()
+		bob.Leave()
+
+		repo.AssertNumberOfCalls(t, "UpdateCourse", 1)
+	})
+
+	t.Run("should not lose edits another replica has not persisted yet", func(t *testing.T) {
+		repo := new(mocks.MockCourseRepository)
+		repo.On("GetCourseByID", mock.Anything, courseID).Return(&model.Course{ID: courseID, Title: "Go", Description: "Basics"}, nil)
+		pub := &loopbackPublisher{}
+		replicaA := newHub(repo, pub)
+		replicaB := newHub(repo, pub)
+
+		alice, err := replicaA.Join(ctx, courseID, "alice")
+		require.NoError(t, err)
+		require.NoError(t, alice.Submit(collab.Message{Type: collab.MessageEdit, Field: collab.FieldTitle, Value: "Go 2"}))
+
+		// replicaB opens the room from the database, which still has "Go".
+		bob, err := replicaB.Join(ctx, courseID, "bob")
+		require.NoError(t, err)
+
+		var snapshot collab.Message
+		for snapshot.Type != collab.MessageSnapshot || snapshot.Course.Title != "Go 2" {
+			snapshot = next(t, bob)
+		}
+
+		require.NoError(t, bob.Submit(collab.Message{Type: collab.MessageEdit, Field: collab.FieldDescription, Value: "Advanced"}))
+		var edit collab.Message
+		for edit.Type != collab.MessageEdit || edit.Field != collab.FieldDescription {
+			edit = next(t, alice)
+		}
+
+		repo.On("UpdateCourse", mock.Anything, mock.AnythingOfType("*model.Course")).Return(nil)
+
+		alice.Leave()
+		bob.Leave()
+
+		repo.AssertNumberOfCalls(t, "UpdateCourse", 2)
+		for _, call := range repo.Calls {
+			if call.Method != "UpdateCourse" {
+				continue
+			}
+			persisted := call.Arguments.Get(1).(*model.Course)
+			assert.Equal(t, "Go 2", persisted.Title)
+			assert.Equal(t, "Advanced", persisted.Description)
+		}
+	})
+
+	t.Run("should persist on behalf of the editors who made the edits", func(t *testing.T) {
+		repo := new(mocks.MockCourseRepository)
+		repo.On("GetCourseByID", mock.Anything, courseID).Return(&model.Course{ID: courseID, Title: "Go", Description: "Basics"}, nil)
+		repo.On("UpdateCourse", mock.Anything, mock.AnythingOfType("*model.Course")).Return(nil)
+		audit := mocks.NewAuditRepositoryStub()
+		hub := newAuditedHub(repo, audit, &loopbackPublisher{})
+
+		alice, err := hub.Join(authz.WithPrincipal(ctx, authz.Principal{ID: "user-alice"}), courseID, "alice")
+		require.NoError(t, err)
+		carol, err := hub.Join(authz.WithPrincipal(ctx, authz.Principal{ID: "user-carol"}), courseID, "carol")
+		require.NoError(t, err)
+		dave, err := hub.Join(authz.WithPrincipal(ctx, authz.Principal{ID: "user-dave"}), courseID, "dave")
+		require.NoError(t, err)
+
+		require.NoError(t, alice.Submit(collab.Message{Type: collab.MessageEdit, Field: collab.FieldTitle, Value: "Go 2"}))
+		require.NoError(t, carol.Submit(collab.Message{Type: collab.MessageEdit, Field: collab.FieldDescription, Value: "Advanced"}))
+		require.NoError(t, alice.Submit(collab.Message{Type: collab.MessageEdit, Field: collab.FieldTitle, Value: "Go 3"}))
+		require.NoError(t, dave.Submit(collab.Message{Type: collab.MessagePresence, Status: collab.PresenceFocus, Field: collab.FieldTitle}))
+
+		dave.Leave()
+		carol.Leave()
+		alice.Leave()
+
+		audit.AssertNumberOfCalls(t, "AppendAuditEntry", 1)
+		entry := audit.Calls[0].Arguments.Get(1).(*model.AuditEntry)
+		assert.Equal(t, model.AuditCourseUpdated, entry.Action)
+		assert.Equal(t, "user-alice", entry.Actor)
+		assert.Equal(t, model.Actors{"user-carol"}, entry.CoActors)
+	})
+
+	t.Run("should reject unknown fields and oversized values", func(t *testing.T) {
+		repo := new(mocks.MockCourseRepository)
+		repo.On("GetCourseByID", mock.Anything, courseID).Return(course, nil)
+		hub := newHub(repo, &loopbackPublisher{})
+
+		client, err := hub.Join(ctx, courseID, "alice")
+		require.NoError(t, err)
+
+		assert.Error(t, client.Submit(collab.Message{Type: collab.MessageEdit, Field: "created_at", Value: "x"}))
+		assert.Error(t, client.Submit(collab.Message{Type: collab.MessageEdit, Field: collab.FieldTitle, Value: string(make([]byte, 101))}))
+		assert.Error(t, client.Submit(collab.Message{Type: collab.MessagePresence, Status: collab.PresenceLeft}))
+	})
+
+	t.Run("should reject edits too large for a notification once escaped", func(t *testing.T) {
+		repo := new(mocks.MockCourseRepository)
+		repo.On("GetCourseByID", mock.Anything, courseID).Return(course, nil)
+		cfg := &config.ServerConfig{Collab: config.CollabConfig{PersistInterval: time.Hour, MaxMessageSize: 7000}}
+		hub := collab.NewHub(cfg, service.NewCourseService(repo, mocks.NewCourseRevisionRepositoryStub(), mocks.NewAuditRepositoryStub(), &mocks.FakeTransactor{}, &mocks.FakeMetrics{}, authz.NewPolicy(&config.AuthzConfig{})), &loopbackPublisher{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		client, err := hub.Join(ctx, courseID, "alice")
+		require.NoError(t, err)
+
+		// Each '<' is escaped to six bytes in the JSON envelope.
+		err = client.Submit(collab.Message{Type: collab.MessageEdit, Field: collab.FieldTitle, Value: strings.Repeat("<", 2000)})
+		assert.True(t, fault.IsCode(err, fault.Invalid))
+	})
+
+	t.Run("should return publish failures to the editor", func(t *testing.T) {
+		repo := new(mocks.MockCourseRepository)
+		repo.On("GetCourseByID", mock.Anything, courseID).Return(course, nil)
+		cfg := &config.ServerConfig{Collab: config.CollabConfig{PersistInterval: time.Hour, MaxMessageSize: 100}}
+		hub := collab.NewHub(cfg, service.NewCourseService(repo, mocks.NewCourseRevisionRepositoryStub(), mocks.NewAuditRepositoryStub(), &mocks.FakeTransactor{}, &mocks.FakeMetrics{}, authz.NewPolicy(&config.AuthzConfig{})), failingPublisher{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		client, err := hub.Join(ctx, courseID, "alice")
+		require.NoError(t, err)
+
+		err = client.Submit(collab.Message{Type: collab.MessageEdit, Field: collab.FieldTitle, Value: "Go 2"})
+		assert.True(t, fault.IsCode(err, fault.InfraError))
+	})
+
+	t.Run("should not let another tenant join an open room", func(t *testing.T) {
+		repo := new(mocks.MockCourseRepository)
+		repo.On("GetCourseByID", mock.Anything, courseID).Return(course, nil).Once()
+		hub := newHub(repo, &loopbackPublisher{})
+
+		alice, err := hub.Join(ctx, courseID, "alice")
+		require.NoError(t, err)
+		defer alice.Leave()
+
+		_, err = hub.Join(tenant.WithID(context.Background(), "school-b"), courseID, "mallory")
+		assert.ErrorIs(t, err, model.ErrCourseNotFound)
+	})
+}
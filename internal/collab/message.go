@@ -0,0 +1,93 @@
+package collab
+
+const NotifyChannel = "course_collab"
+
+type MessageType string
+
+const (
+	MessageSnapshot MessageType = "snapshot"
+	MessageEdit     MessageType = "edit"
+	MessagePresence MessageType = "presence"
+	MessageError    MessageType = "error"
+)
+
+type PresenceStatus string
+
+const (
+	PresenceJoined PresenceStatus = "joined"
+	PresenceLeft   PresenceStatus = "left"
+	PresenceFocus  PresenceStatus = "focus"
+)
+
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+)
+
+type Editor struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type CourseState struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+func (c *CourseState) field(name string) string {
+	if name == FieldTitle {
+		return c.Title
+	}
+	return c.Description
+}
+
+// set changes a field and reports whether name is an editable field.
+func (c *CourseState) set(name, value string) bool {
+	switch name {
+	case FieldTitle:
+		c.Title = value
+	case FieldDescription:
+		c.Description = value
+	default:
+		return false
+	}
+	return true
+}
+
+// Message is exchanged with editors over the WebSocket. Edits and presence
+// changes sent by editors are stamped with a server sequence before being
+// broadcast, so every editor applies them in the same order. The snapshot
+// sent on join carries the editor identity assigned to the connection.
+type Message struct {
+	Type     MessageType    `json:"type"`
+	Seq      uint64         `json:"seq,omitempty"`
+	Editor   *Editor        `json:"editor,omitempty"`
+	Field    string         `json:"field,omitempty"`
+	Value    string         `json:"value,omitempty"`
+	Status   PresenceStatus `json:"status,omitempty"`
+	Course   *CourseState   `json:"course,omitempty"`
+	Editors  []Editor       `json:"editors,omitempty"`
+	ErrorMsg string         `json:"error,omitempty"`
+}
+
+type envelopeKind string
+
+const (
+	envelopeMessage envelopeKind = "message"
+	envelopeSync    envelopeKind = "sync"
+	envelopeState   envelopeKind = "state"
+)
+
+// envelope is the payload sent through Postgres NOTIFY so that editors
+// connected to different replicas see each other's operations. A sync
+// envelope asks the other replicas to announce their local editors and the
+// fields their editors changed, which come back as one state envelope per
+// field.
+type envelope struct {
+	Kind     envelopeKind `json:"kind"`
+	Replica  string       `json:"replica"`
+	CourseID string       `json:"course_id"`
+	Message  *Message     `json:"message,omitempty"`
+	Actor    string       `json:"actor,omitempty"`
+}
@@ -0,0 +1,36 @@
+package collab
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+)
+
+type PostgresPublisher struct {
+	db *sqlx.DB
+}
+
+func NewPostgresPublisher(db *sqlx.DB) Publisher {
+	return &PostgresPublisher{db: db}
+}
+
+func (p *PostgresPublisher) Publish(ctx context.Context, payload string) error {
+	return db.Notify(ctx, p.db, NotifyChannel, payload)
+}
+
+// LocalPublisher hands operations straight to the listener of this process.
+// It is used with the memory and sqlite drivers, where there is a single
+// replica.
+type LocalPublisher struct {
+	listener *db.Listener
+}
+
+func NewLocalPublisher(listener *db.Listener) Publisher {
+	return &LocalPublisher{listener: listener}
+}
+
+func (p *LocalPublisher) Publish(ctx context.Context, payload string) error {
+	return p.listener.Deliver(ctx, NotifyChannel, payload)
+}
@@ -3,19 +3,54 @@ package di
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
 	"go.uber.org/fx"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/collab"
+	"github.com/marcelofabianov/dojo-go/internal/event"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/internal/rpc"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/health"
+	"github.com/marcelofabianov/dojo-go/pkg/idempotency"
+	"github.com/marcelofabianov/dojo-go/pkg/metrics"
+	"github.com/marcelofabianov/dojo-go/pkg/migrate"
+	"github.com/marcelofabianov/dojo-go/pkg/shutdown"
+	"github.com/marcelofabianov/dojo-go/pkg/telemetry"
 )
 
+// stopTimeout only backstops fx: the shutdown coordinator enforces the
+// configured server.shutdown.timeout and must not be cut short by fx.
+const stopTimeout = 2 * time.Minute
+
 func New() *fx.App {
 	return fx.New(
+		fx.StopTimeout(stopTimeout),
+
 		Config,
 		Pkg,
+		Migrate,
+		Health,
+		Shutdown,
+		Metrics,
+		Telemetry,
+		Event,
+		Idempotency,
 		Repository,
 		Service,
+		Collab,
 		Handler,
+		RPC,
 
 		//----
 
@@ -23,7 +58,45 @@ func New() *fx.App {
 	)
 }
 
-func registerHooks(lc fx.Lifecycle, srv *http.Server, logger *slog.Logger) {
+// NewCommand builds the graph of a one-off CLI command: config, logger,
+// database, repositories and services, without servers, background workers
+// or auto-migration. Stopping the app closes the database pool.
+func NewCommand(opts ...fx.Option) *fx.App {
+	return fx.New(append([]fx.Option{
+		fx.NopLogger,
+
+		Config,
+		Pkg,
+		Repository,
+		Service,
+
+		fx.Provide(
+			migrate.New,
+			fx.Annotate(metrics.New, fx.As(new(port.MetricsPort))),
+		),
+		fx.Invoke(func(lc fx.Lifecycle, conn *sqlx.DB) {
+			if conn != nil {
+				lc.Append(fx.StopHook(conn.Close))
+			}
+		}),
+	}, opts...)...)
+}
+
+// registerHooks is invoked last, so its OnStop runs first and hands the
+// whole shutdown sequence to the coordinator. The servers and workers
+// registered by the other hooks only add themselves to it.
+func registerHooks(
+	lc fx.Lifecycle,
+	srv *http.Server,
+	coordinator *shutdown.Coordinator,
+	conn *sqlx.DB,
+	logger *slog.Logger,
+) {
+	coordinator.AddServer("http server", srv)
+	if conn != nil {
+		coordinator.AddCloser("database pool", conn.Close)
+	}
+
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			logger.Info("starting http server", "addr", srv.Addr)
@@ -35,8 +108,220 @@ func registerHooks(lc fx.Lifecycle, srv *http.Server, logger *slog.Logger) {
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
-			logger.Info("stopping http server")
-			return srv.Shutdown(ctx)
+			logger.Info("shutting down")
+			return coordinator.Shutdown(ctx)
+		},
+	})
+}
+
+// applyMigrations runs pending migrations when db.auto_migrate is set. It
+// runs while the graph is built, before any server starts, so requests never
+// see an old schema. The memory driver has no schema to migrate.
+func applyMigrations(cfg *config.DBConfig, conn *sqlx.DB, logger *slog.Logger) error {
+	if !cfg.AutoMigrate || conn == nil {
+		return nil
+	}
+
+	migrator, err := migrate.New(conn, logger)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("applying pending migrations")
+	return migrator.Up(context.Background())
+}
+
+func registerHealthChecks(registry *health.Registry, cfg *config.ServerConfig, conn *sqlx.DB) {
+	if conn != nil {
+		registry.AddReadiness("database", health.DBPing(conn.DB))
+		registry.AddReadiness("migrations", health.MigrationVersion(conn.DB, cfg.Health.MinMigrationVersion))
+	}
+	registry.AddReadiness("disk", health.DiskSpace(cfg.Health.DiskPath, cfg.Health.MinFreeDiskMB<<20))
+}
+
+// registerReplicaHooks keeps checking the read replicas, when there are
+// any, and closes their pools on shutdown. A failing replica does not fail
+// readiness: reads fall back to the primary.
+func registerReplicaHooks(
+	lc fx.Lifecycle,
+	replicas *db.ReplicaSet,
+	coordinator *shutdown.Coordinator,
+	logger *slog.Logger,
+) {
+	if replicas.Len() == 0 {
+		return
+	}
+	coordinator.AddCloser("read replica pools", replicas.Close)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			logger.Info("starting read replica health check")
+			coordinator.Go("read replica health check", replicas.Run)
+			return nil
+		},
+	})
+}
+
+// registerMetricsHooks exposes the pool stats of the database, when there is
+// one, and serves the metrics on the API router, or on the admin listener
+// when a port is set.
+func registerMetricsHooks(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	m *metrics.Metrics,
+	router *chi.Mux,
+	conn *sqlx.DB,
+	coordinator *shutdown.Coordinator,
+	logger *slog.Logger,
+) error {
+	if conn != nil {
+		if err := m.RegisterDB(conn.DB, cfg.DB.Name); err != nil {
+			return err
+		}
+	}
+
+	metricsCfg := cfg.Server.Metrics
+	if !metricsCfg.Enabled {
+		return nil
+	}
+
+	if metricsCfg.Port == 0 {
+		router.Handle(metricsCfg.Path, m.Handler())
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsCfg.Path, m.Handler())
+	srv := &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", metricsCfg.Host, metricsCfg.Port),
+		Handler:           mux,
+		ReadHeaderTimeout: cfg.Server.API.ReadTimeout,
+	}
+	coordinator.AddServer("metrics server", srv)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			logger.Info("starting metrics server", "addr", srv.Addr)
+			go func() {
+				if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Error("failed to start metrics server", "error", err)
+				}
+			}()
+			return nil
+		},
+	})
+
+	return nil
+}
+
+// registerTelemetryHooks flushes pending spans on shutdown. The provider is
+// installed globally when it is built, before any server starts.
+func registerTelemetryHooks(lc fx.Lifecycle, provider *telemetry.Provider, cfg *config.TracingConfig, logger *slog.Logger) {
+	logger.Info("tracing configured", "exporter", cfg.Exporter, "sample_ratio", cfg.SampleRatio)
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			logger.Info("flushing traces")
+			return provider.Shutdown(ctx)
+		},
+	})
+}
+
+func registerEventHooks(
+	lc fx.Lifecycle,
+	listener *db.Listener,
+	broker *event.Broker,
+	coordinator *shutdown.Coordinator,
+	logger *slog.Logger,
+) {
+	listener.Listen(event.CourseEventsChannel, broker.HandleNotification)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			logger.Info("starting course event listener")
+			coordinator.Go("course event listener", listener.Run)
+			return nil
+		},
+	})
+}
+
+func registerCollabHooks(
+	lc fx.Lifecycle,
+	listener *db.Listener,
+	hub *collab.Hub,
+	coordinator *shutdown.Coordinator,
+	logger *slog.Logger,
+) {
+	listener.Listen(collab.NotifyChannel, hub.HandleNotification)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			logger.Info("starting collab persistence worker")
+			coordinator.Go("collab persistence worker", hub.Run)
+			return nil
+		},
+	})
+}
+
+func registerIdempotencyHooks(
+	lc fx.Lifecycle,
+	middleware *idempotency.Middleware,
+	coordinator *shutdown.Coordinator,
+	logger *slog.Logger,
+) {
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			logger.Info("starting idempotency key cleanup worker")
+			coordinator.Go("idempotency key cleanup worker", middleware.RunCleanup)
+			return nil
+		},
+	})
+}
+
+func registerGRPCHooks(
+	lc fx.Lifecycle,
+	cfg *config.ServerConfig,
+	srv *grpc.Server,
+	healthServer *grpchealth.Server,
+	coordinator *shutdown.Coordinator,
+	logger *slog.Logger,
+) {
+	if !cfg.GRPC.Enabled {
+		return
+	}
+
+	coordinator.AddServer("grpc server", shutdown.ServerFunc(func(ctx context.Context) error {
+		healthServer.Shutdown()
+
+		stopped := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+			return nil
+		case <-ctx.Done():
+			srv.Stop()
+			return ctx.Err()
+		}
+	}))
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			lis, err := net.Listen("tcp", rpc.Addr(cfg))
+			if err != nil {
+				return err
+			}
+
+			logger.Info("starting grpc server", "addr", lis.Addr().String())
+			go func() {
+				if err := srv.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+					logger.Error("failed to start grpc server", "error", err)
+				}
+			}()
+			return nil
 		},
 	})
 }
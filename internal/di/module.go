@@ -4,11 +4,23 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/collab"
+	"github.com/marcelofabianov/dojo-go/internal/event"
 	"github.com/marcelofabianov/dojo-go/internal/handler"
+	"github.com/marcelofabianov/dojo-go/internal/port"
 	"github.com/marcelofabianov/dojo-go/internal/repository"
+	"github.com/marcelofabianov/dojo-go/internal/rpc"
 	"github.com/marcelofabianov/dojo-go/internal/service"
+	"github.com/marcelofabianov/dojo-go/pkg/auth"
 	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/health"
+	"github.com/marcelofabianov/dojo-go/pkg/idempotency"
 	"github.com/marcelofabianov/dojo-go/pkg/logger"
+	"github.com/marcelofabianov/dojo-go/pkg/metrics"
+	"github.com/marcelofabianov/dojo-go/pkg/shutdown"
+	"github.com/marcelofabianov/dojo-go/pkg/telemetry"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
 	"github.com/marcelofabianov/dojo-go/pkg/validator"
 	"github.com/marcelofabianov/dojo-go/pkg/web"
 )
@@ -21,7 +33,9 @@ var Config = fx.Module("config",
 		func(cfg *config.Config) *config.GeneralConfig { return &cfg.General },
 		func(cfg *config.Config) *config.LoggerConfig { return &cfg.Logger },
 		func(cfg *config.Config) *config.ServerConfig { return &cfg.Server },
+		func(cfg *config.Config) *config.AuthzConfig { return &cfg.Authz },
 		func(cfg *config.Config) *config.DBConfig { return &cfg.DB },
+		func(cfg *config.Config) *config.TracingConfig { return &cfg.Tracing },
 	),
 )
 
@@ -30,18 +44,105 @@ var Config = fx.Module("config",
 var Pkg = fx.Module("pkg",
 	fx.Provide(
 		logger.NewSlogLogger,
-		db.NewPostgresConnection,
+		db.Open,
+		db.OpenReplicas,
+		db.NewListener,
 		validator.NewValidator,
+		auth.NewVerifier,
+		auth.NewMiddleware,
+		tenant.NewMiddleware,
 		web.NewRouter,
 		web.NewServer,
 	),
 )
 
+// --- Migrate ---
+
+var Migrate = fx.Module("migrate",
+	fx.Invoke(applyMigrations),
+)
+
+// --- Health ---
+
+var Health = fx.Module("health",
+	fx.Provide(
+		fx.Annotate(health.NewRegistry, fx.As(fx.Self()), fx.As(new(shutdown.Drainer))),
+	),
+
+	fx.Invoke(registerHealthChecks, registerReplicaHooks),
+)
+
+// --- Shutdown ---
+
+var Shutdown = fx.Module("shutdown",
+	fx.Provide(
+		shutdown.NewCoordinator,
+	),
+)
+
+// --- Metrics ---
+
+var Metrics = fx.Module("metrics",
+	fx.Provide(
+		fx.Annotate(metrics.New, fx.As(fx.Self()), fx.As(new(port.MetricsPort))),
+	),
+
+	fx.Invoke(registerMetricsHooks),
+)
+
+// --- Telemetry ---
+
+var Telemetry = fx.Module("telemetry",
+	fx.Provide(
+		telemetry.NewProvider,
+	),
+
+	fx.Invoke(registerTelemetryHooks),
+)
+
+// --- Event ---
+
+var Event = fx.Module("event",
+	fx.Provide(
+		event.NewBroker,
+	),
+
+	fx.Invoke(registerEventHooks),
+)
+
+// --- Collab ---
+
+var Collab = fx.Module("collab",
+	fx.Provide(
+		newCollabPublisher,
+		collab.NewHub,
+	),
+
+	fx.Invoke(registerCollabHooks),
+)
+
+// --- Idempotency ---
+
+var Idempotency = fx.Module("idempotency",
+	fx.Provide(
+		newIdempotencyStore,
+		newIdempotencyOwner,
+		idempotency.NewMiddleware,
+	),
+
+	fx.Invoke(registerIdempotencyHooks),
+)
+
 // --- Repository ---
 
 var Repository = fx.Module("repository",
 	fx.Provide(
-		repository.NewPostgresCourseRepository,
+		repository.NewMemoryStore,
+		newCourseRepository,
+		newCourseRevisionRepository,
+		newAPIKeyRepository,
+		newAuditRepository,
+		newTransactor,
 	),
 )
 
@@ -49,7 +150,11 @@ var Repository = fx.Module("repository",
 
 var Service = fx.Module("service",
 	fx.Provide(
+		authz.NewPolicy,
 		service.NewCourseService,
+		service.NewCourseBatchService,
+		service.NewAPIKeyService,
+		service.NewAuditService,
 	),
 )
 
@@ -61,7 +166,34 @@ var Handler = fx.Module("handler",
 		handler.NewGetCourseHandler,
 		handler.NewDeleteCourseHandler,
 		handler.NewUpdateCourseHandler,
+		handler.NewListCourseRevisionsHandler,
+		handler.NewGetCourseRevisionHandler,
+		handler.NewDiffCourseRevisionsHandler,
+		handler.NewRollbackCourseHandler,
+		handler.NewCourseEventsHandler,
+		handler.NewCourseCollabHandler,
+		handler.NewGraphQLHandler,
+		handler.NewBatchCoursesHandler,
+		handler.NewCreateAPIKeyHandler,
+		handler.NewListAPIKeysHandler,
+		handler.NewRevokeAPIKeyHandler,
+		handler.NewRotateAPIKeyHandler,
+		handler.NewListCourseAuditHandler,
+		handler.NewExportCourseAuditHandler,
+		handler.NewAPIKeyMiddleware,
 	),
 
 	fx.Invoke(handler.RegisterRoutes),
 )
+
+// --- RPC ---
+
+var RPC = fx.Module("rpc",
+	fx.Provide(
+		rpc.NewCourseServer,
+		rpc.NewHealthServer,
+		rpc.NewServer,
+	),
+
+	fx.Invoke(registerGRPCHooks),
+)
@@ -0,0 +1,104 @@
+package di
+
+import (
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/collab"
+	"github.com/marcelofabianov/dojo-go/internal/event"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/internal/repository"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/idempotency"
+)
+
+// The providers below pick the adapter for db.driver. With the memory driver
+// the connection is nil and every repository shares one MemoryStore. Only
+// Postgres has LISTEN/NOTIFY; the other drivers publish in process.
+
+func newCourseRepository(
+	cfg *config.DBConfig,
+	conn *sqlx.DB,
+	store *repository.MemoryStore,
+	transactor port.TransactorPort,
+	listener *db.Listener,
+	logger *slog.Logger,
+) port.CourseRepositoryPort {
+	switch cfg.Driver {
+	case db.DriverMemory:
+		repo := repository.NewMemoryCourseRepository(store)
+		return event.NewNotifyingCourseRepository(repo, transactor, listener, logger)
+	case db.DriverSQLite:
+		repo := repository.NewSQLiteCourseRepository(conn, cfg)
+		return event.NewNotifyingCourseRepository(repo, transactor, listener, logger)
+	default:
+		return repository.NewPostgresCourseRepository(conn, cfg)
+	}
+}
+
+func newCourseRevisionRepository(cfg *config.DBConfig, conn *sqlx.DB, store *repository.MemoryStore) port.CourseRevisionRepositoryPort {
+	switch cfg.Driver {
+	case db.DriverMemory:
+		return repository.NewMemoryCourseRevisionRepository(store)
+	case db.DriverSQLite:
+		return repository.NewSQLiteCourseRevisionRepository(conn, cfg)
+	default:
+		return repository.NewPostgresCourseRevisionRepository(conn, cfg)
+	}
+}
+
+func newAPIKeyRepository(cfg *config.DBConfig, conn *sqlx.DB, store *repository.MemoryStore) port.APIKeyRepositoryPort {
+	switch cfg.Driver {
+	case db.DriverMemory:
+		return repository.NewMemoryAPIKeyRepository(store)
+	case db.DriverSQLite:
+		return repository.NewSQLiteAPIKeyRepository(conn, cfg)
+	default:
+		return repository.NewPostgresAPIKeyRepository(conn, cfg)
+	}
+}
+
+func newAuditRepository(cfg *config.DBConfig, conn *sqlx.DB, store *repository.MemoryStore) port.AuditRepositoryPort {
+	switch cfg.Driver {
+	case db.DriverMemory:
+		return repository.NewMemoryAuditRepository(store)
+	case db.DriverSQLite:
+		return repository.NewSQLiteAuditRepository(conn, cfg)
+	default:
+		return repository.NewPostgresAuditRepository(conn, cfg)
+	}
+}
+
+func newTransactor(cfg *config.DBConfig, conn *sqlx.DB) port.TransactorPort {
+	if cfg.Driver == db.DriverMemory {
+		return db.NewMemoryTransactor()
+	}
+	return db.NewTransactor(conn)
+}
+
+func newIdempotencyStore(cfg *config.DBConfig, conn *sqlx.DB) idempotency.Store {
+	switch cfg.Driver {
+	case db.DriverMemory:
+		return idempotency.NewMemoryStore()
+	case db.DriverSQLite:
+		return idempotency.NewSQLiteStore(conn, cfg)
+	default:
+		return idempotency.NewPostgresStore(conn, cfg)
+	}
+}
+
+// newIdempotencyOwner scopes idempotency keys to the principal named in
+// audit records, so API keys and users never share responses.
+func newIdempotencyOwner() idempotency.Owner {
+	return authz.Actor
+}
+
+func newCollabPublisher(cfg *config.DBConfig, conn *sqlx.DB, listener *db.Listener) collab.Publisher {
+	if cfg.Driver != db.DriverPostgres {
+		return collab.NewLocalPublisher(listener)
+	}
+	return collab.NewPostgresPublisher(conn)
+}
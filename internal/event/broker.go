@@ -0,0 +1,152 @@
+package event
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/marcelofabianov/dojo-go/config"
+)
+
+const subscriberBufferSize = 64
+
+// Broker fans course events out to subscribers and keeps the most recent
+// events in a bounded buffer so that clients can resume from a Last-Event-ID.
+type Broker struct {
+	mu          sync.Mutex
+	logger      *slog.Logger
+	replay      []CourseEvent
+	capacity    int
+	next        int
+	subscribers map[*Subscription]struct{}
+}
+
+type Subscription struct {
+	events    chan CourseEvent
+	tenantID  string
+	courseIDs map[string]struct{}
+	broker    *Broker
+	once      sync.Once
+}
+
+func NewBroker(cfg *config.ServerConfig, logger *slog.Logger) *Broker {
+	capacity := cfg.Events.ReplayBufferSize
+	if capacity <= 0 {
+		capacity = 1
+	}
+
+	return &Broker{
+		logger:      logger,
+		replay:      make([]CourseEvent, 0, capacity),
+		capacity:    capacity,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// HandleNotification is registered as the db.Listener handler for the
+// course events channel.
+func (b *Broker) HandleNotification(payload string) {
+	e, err := ParseNotification(payload)
+	if err != nil {
+		b.logger.Error("failed to parse course event", "error", err, "payload", payload)
+		return
+	}
+
+	b.Publish(e)
+}
+
+func (b *Broker) Publish(e CourseEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.replay) < b.capacity {
+		b.replay = append(b.replay, e)
+	} else {
+		b.replay[b.next] = e
+		b.next = (b.next + 1) % b.capacity
+	}
+
+	for sub := range b.subscribers {
+		if !sub.matches(e) {
+			continue
+		}
+
+		select {
+		case sub.events <- e:
+		default:
+			// Slow consumers are disconnected instead of blocking the broker;
+			// they can resume from their last event id.
+			b.logger.Warn("dropping slow course event subscriber")
+			b.remove(sub)
+		}
+	}
+}
+
+// Subscribe registers a subscriber and returns the buffered events newer than
+// lastEventID. Registration and replay happen under the same lock so no event
+// is lost or delivered twice between the two. Subscribers only receive
+// events of their tenant; an empty courseIDs list subscribes to every course
+// of the tenant.
+func (b *Broker) Subscribe(tenantID string, lastEventID uint64, courseIDs []string) (*Subscription, []CourseEvent) {
+	sub := &Subscription{
+		events:   make(chan CourseEvent, subscriberBufferSize),
+		tenantID: tenantID,
+		broker:   b,
+	}
+
+	if len(courseIDs) > 0 {
+		sub.courseIDs = make(map[string]struct{}, len(courseIDs))
+		for _, id := range courseIDs {
+			sub.courseIDs[id] = struct{}{}
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []CourseEvent
+	if lastEventID > 0 {
+		for i := range b.replay {
+			e := b.replay[(b.next+i)%len(b.replay)]
+			if e.ID > lastEventID && sub.matches(e) {
+				replay = append(replay, e)
+			}
+		}
+	}
+
+	b.subscribers[sub] = struct{}{}
+
+	return sub, replay
+}
+
+func (b *Broker) remove(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
+
+// Events is closed when the subscription ends, either through Close or
+// because the subscriber fell too far behind.
+func (s *Subscription) Events() <-chan CourseEvent {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.broker.mu.Lock()
+		defer s.broker.mu.Unlock()
+		s.broker.remove(s)
+	})
+}
+
+func (s *Subscription) matches(e CourseEvent) bool {
+	if e.TenantID != s.tenantID {
+		return false
+	}
+	if s.courseIDs == nil {
+		return true
+	}
+	_, ok := s.courseIDs[e.CourseID]
+	return ok
+}
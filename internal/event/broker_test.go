@@ -0,0 +1,138 @@
+//go:build unit
+
+package event_test
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/event"
+)
+
+func newBroker(capacity int) *event.Broker {
+	cfg := &config.ServerConfig{Events: config.EventsConfig{ReplayBufferSize: capacity}}
+	return event.NewBroker(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+const testTenant = "school-a"
+
+func courseEvent(id uint64, courseID string) event.CourseEvent {
+	return event.CourseEvent{ID: id, Type: event.CourseUpdated, CourseID: courseID, TenantID: testTenant, OccurredAt: time.Now()}
+}
+
+func TestBroker_Subscribe(t *testing.T) {
+	t.Run("should replay only events newer than last event id", func(t *testing.T) {
+		b := newBroker(10)
+		for i := uint64(1); i <= 5; i++ {
+			b.Publish(courseEvent(i, "course-a"))
+		}
+
+		sub, replay := b.Subscribe(testTenant, 3, nil)
+		defer sub.Close()
+
+		require.Len(t, replay, 2)
+		assert.Equal(t, uint64(4), replay[0].ID)
+		assert.Equal(t, uint64(5), replay[1].ID)
+	})
+
+	t.Run("should keep only the most recent events in the replay buffer", func(t *testing.T) {
+		b := newBroker(3)
+		for i := uint64(1); i <= 5; i++ {
+			b.Publish(courseEvent(i, "course-a"))
+		}
+
+		sub, replay := b.Subscribe(testTenant, 1, nil)
+		defer sub.Close()
+
+		require.Len(t, replay, 3)
+		assert.Equal(t, []uint64{3, 4, 5}, []uint64{replay[0].ID, replay[1].ID, replay[2].ID})
+	})
+
+	t.Run("should not replay without a last event id", func(t *testing.T) {
+		b := newBroker(10)
+		b.Publish(courseEvent(1, "course-a"))
+
+		sub, replay := b.Subscribe(testTenant, 0, nil)
+		defer sub.Close()
+
+		assert.Empty(t, replay)
+	})
+
+	t.Run("should filter replay and live events by course id", func(t *testing.T) {
+		b := newBroker(10)
+		b.Publish(courseEvent(1, "course-a"))
+		b.Publish(courseEvent(2, "course-b"))
+
+		sub, replay := b.Subscribe(testTenant, 0, []string{"course-b"})
+		defer sub.Close()
+		assert.Empty(t, replay)
+
+		b.Publish(courseEvent(3, "course-a"))
+		b.Publish(courseEvent(4, "course-b"))
+
+		e := <-sub.Events()
+		assert.Equal(t, uint64(4), e.ID)
+		assert.Empty(t, sub.Events())
+	})
+
+	t.Run("should never deliver events of another tenant", func(t *testing.T) {
+		otherTenant := func(id uint64) event.CourseEvent {
+			e := courseEvent(id, "course-a")
+			e.TenantID = "school-b"
+			return e
+		}
+
+		b := newBroker(10)
+		b.Publish(courseEvent(1, "course-a"))
+		b.Publish(otherTenant(2))
+		b.Publish(courseEvent(3, "course-a"))
+
+		sub, replay := b.Subscribe(testTenant, 1, nil)
+		defer sub.Close()
+		require.Len(t, replay, 1)
+		assert.Equal(t, uint64(3), replay[0].ID)
+
+		b.Publish(otherTenant(4))
+		b.Publish(courseEvent(5, "course-a"))
+
+		e := <-sub.Events()
+		assert.Equal(t, uint64(5), e.ID)
+		assert.Empty(t, sub.Events())
+	})
+
+	t.Run("should close the subscription channel on close", func(t *testing.T) {
+		b := newBroker(10)
+		sub, _ := b.Subscribe(testTenant, 0, nil)
+
+		sub.Close()
+		sub.Close()
+
+		_, ok := <-sub.Events()
+		assert.False(t, ok)
+	})
+}
+
+func TestParseNotification(t *testing.T) {
+	t.Run("should map trigger operations to event types", func(t *testing.T) {
+		payload := `{"id":7,"operation":"DELETE","course_id":"c1","tenant_id":"school-a","occurred_at":"2025-10-19T12:00:00Z"}`
+
+		e, err := event.ParseNotification(payload)
+
+		require.NoError(t, err)
+		assert.Equal(t, uint64(7), e.ID)
+		assert.Equal(t, event.CourseDeleted, e.Type)
+		assert.Equal(t, "c1", e.CourseID)
+		assert.Equal(t, "school-a", e.TenantID)
+	})
+
+	t.Run("should reject unknown operations", func(t *testing.T) {
+		_, err := event.ParseNotification(`{"id":1,"operation":"TRUNCATE"}`)
+		assert.Error(t, err)
+	})
+}
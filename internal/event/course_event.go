@@ -0,0 +1,69 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+const CourseEventsChannel = "course_events"
+
+type Type string
+
+const (
+	CourseCreated Type = "course.created"
+	CourseUpdated Type = "course.updated"
+	CourseDeleted Type = "course.deleted"
+)
+
+var operationTypes = map[string]Type{
+	"INSERT": CourseCreated,
+	"UPDATE": CourseUpdated,
+	"DELETE": CourseDeleted,
+}
+
+type CourseEvent struct {
+	ID         uint64    `json:"id"`
+	Type       Type      `json:"type"`
+	CourseID   string    `json:"course_id"`
+	TenantID   string    `json:"-"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type notificationPayload struct {
+	ID         uint64    `json:"id"`
+	Operation  string    `json:"operation"`
+	CourseID   string    `json:"course_id"`
+	TenantID   string    `json:"tenant_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// ParseNotification decodes the payload sent by the notify_course_change
+// trigger into a CourseEvent.
+func ParseNotification(payload string) (CourseEvent, error) {
+	var p notificationPayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return CourseEvent{}, fault.Wrap(err,
+			"failed to decode course event notification",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	eventType, ok := operationTypes[p.Operation]
+	if !ok {
+		return CourseEvent{}, fault.New(
+			"unknown course event operation",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("operation", p.Operation),
+		)
+	}
+
+	return CourseEvent{
+		ID:         p.ID,
+		Type:       eventType,
+		CourseID:   p.CourseID,
+		TenantID:   p.TenantID,
+		OccurredAt: p.OccurredAt,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+// NotifyingCourseRepository stands in for the notify_course_change trigger
+// with the memory and sqlite drivers. Every write that commits is delivered
+// to the listener of this process with the payload the trigger would send,
+// so the broker sees the same events whatever the driver.
+type NotifyingCourseRepository struct {
+	port.CourseRepositoryPort
+	transactor port.TransactorPort
+	listener   *db.Listener
+	logger     *slog.Logger
+	seq        atomic.Uint64
+}
+
+func NewNotifyingCourseRepository(
+	repo port.CourseRepositoryPort,
+	transactor port.TransactorPort,
+	listener *db.Listener,
+	logger *slog.Logger,
+) *NotifyingCourseRepository {
+	return &NotifyingCourseRepository{
+		CourseRepositoryPort: repo,
+		transactor:           transactor,
+		listener:             listener,
+		logger:               logger,
+	}
+}
+
+func (r *NotifyingCourseRepository) CreateCourse(ctx context.Context, course *model.Course) error {
+	if err := r.CourseRepositoryPort.CreateCourse(ctx, course); err != nil {
+		return err
+	}
+	r.notify(ctx, "INSERT", course.ID, course.TenantID)
+	return nil
+}
+
+func (r *NotifyingCourseRepository) UpdateCourse(ctx context.Context, course *model.Course) error {
+	if err := r.CourseRepositoryPort.UpdateCourse(ctx, course); err != nil {
+		return err
+	}
+	r.notify(ctx, "UPDATE", course.ID, course.TenantID)
+	return nil
+}
+
+func (r *NotifyingCourseRepository) DeleteCourseByID(ctx context.Context, id string) error {
+	if err := r.CourseRepositoryPort.DeleteCourseByID(ctx, id); err != nil {
+		return err
+	}
+	tenantID, _ := tenant.FromContext(ctx)
+	r.notify(ctx, "DELETE", id, tenantID)
+	return nil
+}
+
+// notify numbers the event now, like the trigger does inside the
+// transaction, but delivers it only once the outermost transaction commits.
+func (r *NotifyingCourseRepository) notify(ctx context.Context, operation, courseID, tenantID string) {
+	payload, err := json.Marshal(notificationPayload{
+		ID:         r.seq.Add(1),
+		Operation:  operation,
+		CourseID:   courseID,
+		TenantID:   tenantID,
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		r.logger.Error("failed to encode course event", "course_id", courseID, "error", err)
+		return
+	}
+
+	r.transactor.AfterCommit(ctx, func() {
+		if err := r.listener.Deliver(context.WithoutCancel(ctx), CourseEventsChannel, string(payload)); err != nil {
+			r.logger.Error("failed to deliver course event", "course_id", courseID, "error", err)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+//go:build unit
+
+package event_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/event"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/internal/repository"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/migrate"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+// subscribeLocal wires repo to a broker the way the app does for drivers
+// without LISTEN/NOTIFY and subscribes to the events of testTenant.
+func subscribeLocal(t *testing.T, driver string, repo port.CourseRepositoryPort, transactor port.TransactorPort) (port.CourseRepositoryPort, *event.Subscription) {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	listener := db.NewListener(&config.DBConfig{Driver: driver}, logger)
+	broker := newBroker(10)
+	listener.Listen(event.CourseEventsChannel, broker.HandleNotification)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go listener.Run(ctx)
+
+	sub, _ := broker.Subscribe(testTenant, 0, nil)
+	t.Cleanup(sub.Close)
+
+	return event.NewNotifyingCourseRepository(repo, transactor, listener, logger), sub
+}
+
+func nextEvent(t *testing.T, sub *event.Subscription) event.CourseEvent {
+	t.Helper()
+
+	select {
+	case e := <-sub.Events():
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("no course event delivered")
+		return event.CourseEvent{}
+	}
+}
+
+func runNotifyingCourseRepository(t *testing.T, driver string, newRepo func(t *testing.T) (port.CourseRepositoryPort, port.TransactorPort)) {
+	ctx := tenant.WithID(context.Background(), testTenant)
+
+	t.Run("should deliver an event for every committed write", func(t *testing.T) {
+		base, transactor := newRepo(t)
+		repo, sub := subscribeLocal(t, driver, base, transactor)
+
+		course, err := model.NewCourse(model.NewCourseInput{Title: "Go", Description: "Dojo"})
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateCourse(ctx, course))
+
+		created := nextEvent(t, sub)
+		assert.Equal(t, event.CourseCreated, created.Type)
+		assert.Equal(t, course.ID, created.CourseID)
+		assert.Equal(t, testTenant, created.TenantID)
+
+		course.Title = "Go avançado"
+		require.NoError(t, repo.UpdateCourse(ctx, course))
+		require.NoError(t, repo.DeleteCourseByID(ctx, course.ID))
+
+		updated := nextEvent(t, sub)
+		deleted := nextEvent(t, sub)
+		assert.Equal(t, event.CourseUpdated, updated.Type)
+		assert.Equal(t, event.CourseDeleted, deleted.Type)
+		assert.Equal(t, course.ID, deleted.CourseID)
+		assert.Less(t, created.ID, updated.ID)
+		assert.Less(t, updated.ID, deleted.ID)
+	})
+
+	t.Run("should not deliver writes that were rolled back", func(t *testing.T) {
+		base, transactor := newRepo(t)
+		repo, sub := subscribeLocal(t, driver, base, transactor)
+		errAbort := errors.New("abort")
+
+		err := transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+			course, err := model.NewCourse(model.NewCourseInput{Title: "Go", Description: "Dojo"})
+			require.NoError(t, err)
+			require.NoError(t, repo.CreateCourse(ctx, course))
+			return errAbort
+		})
+		require.ErrorIs(t, err, errAbort)
+
+		course, err := model.NewCourse(model.NewCourseInput{Title: "Rust", Description: "Dojo"})
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateCourse(ctx, course))
+
+		assert.Equal(t, course.ID, nextEvent(t, sub).CourseID)
+	})
+}
+
+func TestNotifyingCourseRepository_Memory(t *testing.T) {
+	runNotifyingCourseRepository(t, db.DriverMemory, func(t *testing.T) (port.CourseRepositoryPort, port.TransactorPort) {
+		return repository.NewMemoryCourseRepository(repository.NewMemoryStore()), db.NewMemoryTransactor()
+	})
+}
+
+func TestNotifyingCourseRepository_SQLite(t *testing.T) {
+	runNotifyingCourseRepository(t, db.DriverSQLite, func(t *testing.T) (port.CourseRepositoryPort, port.TransactorPort) {
+		cfg := &config.DBConfig{
+			Driver:       db.DriverSQLite,
+			Path:         filepath.Join(t.TempDir(), "dojo.db"),
+			MaxOpenConns: 4,
+			MaxIdleConns: 4,
+			QueryTimeout: time.Second,
+			ExecTimeout:  time.Second,
+		}
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+		conn, err := db.NewSQLiteConnection(cfg, logger)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+
+		migrator, err := migrate.New(conn, logger)
+		require.NoError(t, err)
+		require.NoError(t, migrator.Up(context.Background()))
+
+		return repository.NewSQLiteCourseRepository(conn, cfg), db.NewTransactor(conn)
+	})
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/auth"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+type APIKeyMiddleware struct {
+	apiKeyService port.APIKeyServicePort
+}
+
+func NewAPIKeyMiddleware(apiKeyService port.APIKeyServicePort) *APIKeyMiddleware {
+	return &APIKeyMiddleware{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// Handler authenticates requests carrying an X-API-Key header. The key's
+// scopes become the permissions of the request principal, its tenant becomes
+// the request tenant and the request is marked as authenticated, so the JWT
+// middleware that follows skips it.
+// Requests without the header are passed through untouched.
+func (m *APIKeyMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get(APIKeyHeader)
+		if token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		logger := web.GetLogger(ctx)
+
+		key, err := m.apiKeyService.AuthenticateAPIKey(ctx, token)
+		if err != nil {
+			logger.Warn("api key rejected", "error", err)
+			web.Error(w, r, err)
+			return
+		}
+
+		permissions := make([]authz.Permission, 0, len(key.Scopes))
+		for _, scope := range key.Scopes {
+			permissions = append(permissions, authz.Permission(scope))
+		}
+
+		ctx = authz.WithPrincipal(ctx, authz.Principal{ID: "apikey:" + key.ID, Permissions: permissions})
+		ctx = tenant.WithID(ctx, key.TenantID)
+		ctx = auth.MarkAuthenticated(ctx)
+		ctx = web.WithLoggerAttrs(ctx, "api_key_id", key.ID, "api_key_prefix", key.Prefix)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/validator"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type BatchOperationRequest struct {
+	Op          string `json:"op" validate:"required,oneof=create update delete"`
+	ID          string `json:"id,omitempty" validate:"required_unless=Op create,omitempty,uuid"`
+	Title       string `json:"title,omitempty" validate:"required_unless=Op delete"`
+	Description string `json:"description,omitempty" validate:"required_unless=Op delete"`
+}
+
+type BatchCoursesRequest struct {
+	Mode       string                  `json:"mode" validate:"omitempty,oneof=atomic best_effort" example:"atomic"`
+	Operations []BatchOperationRequest `json:"operations" validate:"required,min=1"`
+}
+
+type BatchOperationResult struct {
+	Index  int                   `json:"index"`
+	Op     string                `json:"op"`
+	Status int                   `json:"status"`
+	Course *CreateCourseResponse `json:"course,omitempty"`
+	Error  *fault.ErrorResponse  `json:"error,omitempty"`
+}
+
+type BatchCoursesResponse struct {
+	Mode      string                 `json:"mode"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Results   []BatchOperationResult `json:"results"`
+}
+
+type BatchCoursesHandler struct {
+	validator     *validator.Validator
+	batchService  port.CourseBatchServicePort
+	maxOperations int
+}
+
+func NewBatchCoursesHandler(
+	cfg *config.ServerConfig,
+	validator *validator.Validator,
+	batchService port.CourseBatchServicePort,
+) *BatchCoursesHandler {
+	return &BatchCoursesHandler{
+		validator:     validator,
+		batchService:  batchService,
+		maxOperations: cfg.Batch.MaxOperations,
+	}
+}
+
+// Handle godoc
+// @Summary      Batch course operations
+// @Description  Applies a list of create, update and delete operations, either all-or-nothing ("atomic", default) or independently ("best_effort"). Each operation reports its own status and error.
+// @Tags         Courses
+// @Accept       json
+// @Produce      json
+// @Param        batch  body      BatchCoursesRequest  true  "Operations to apply"
+// @Success      200    {object}  BatchCoursesResponse "All operations succeeded"
+// @Success      207    {object}  BatchCoursesResponse "At least one operation failed"
+// @Failure      400    {object}  ErrorResponse "Validation errors"
+// @Router       /courses:batch [post]
+func (h *BatchCoursesHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := web.GetLogger(ctx)
+
+	var req BatchCoursesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", "error", err)
+		web.ErrDecodeRequestBody(err, w, r)
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		logger.Warn("request validation failed", "error", err)
+		web.Error(w, r, err)
+		return
+	}
+
+	if len(req.Operations) > h.maxOperations {
+		logger.Warn("batch exceeds maximum number of operations", "operations", len(req.Operations))
+		web.Error(w, r, fault.New("batch exceeds the maximum number of operations",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("max_operations", h.maxOperations),
+		))
+		return
+	}
+
+	mode := model.BatchMode(req.Mode)
+	if mode == "" {
+		mode = model.BatchAtomic
+	}
+
+	results := h.execute(r, mode, req.Operations)
+
+	response := BatchCoursesResponse{
+		Mode:    string(mode),
+		Results: make([]BatchOperationResult, len(results)),
+	}
+	for i, result := range results {
+		response.Results[i] = batchOperationResult(i, req.Operations[i].Op, result)
+		if result.Err != nil {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+
+	status := http.StatusOK
+	if response.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+
+	logger.Info("course batch processed", "mode", mode, "succeeded", response.Succeeded, "failed", response.Failed)
+	web.Success(w, r, status, response)
+}
+
+// execute validates every operation before running the batch. Invalid
+// operations never reach the service; in atomic mode a single invalid
+// operation prevents the whole batch from running.
+func (h *BatchCoursesHandler) execute(r *http.Request, mode model.BatchMode, reqs []BatchOperationRequest) []model.BatchResult {
+	results := make([]model.BatchResult, len(reqs))
+	ops := make([]model.BatchOperation, 0, len(reqs))
+	indexes := make([]int, 0, len(reqs))
+	invalid := -1
+
+	for i, req := range reqs {
+		if err := h.validator.Validate(req); err != nil {
+			results[i] = model.BatchResult{Err: err}
+			if invalid == -1 {
+				invalid = i
+			}
+			continue
+		}
+		ops = append(ops, model.BatchOperation{
+			Type:        model.BatchOperationType(req.Op),
+			ID:          req.ID,
+			Title:       req.Title,
+			Description: req.Description,
+		})
+		indexes = append(indexes, i)
+	}
+
+	if mode == model.BatchAtomic && invalid != -1 {
+		for _, i := range indexes {
+			results[i] = model.BatchResult{Err: fault.New("operation not executed because another operation in the batch is invalid",
+				fault.WithCode(fault.Conflict),
+				fault.WithContext("failed_index", invalid),
+			)}
+		}
+		return results
+	}
+
+	for j, result := range h.batchService.ExecuteBatch(r.Context(), mode, ops) {
+		results[indexes[j]] = result
+	}
+
+	return results
+}
+
+func batchOperationResult(index int, op string, result model.BatchResult) BatchOperationResult {
+	res := BatchOperationResult{Index: index, Op: op}
+
+	if result.Err != nil {
+		err := result.Err
+		if errors.Is(err, model.ErrCourseNotFound) {
+			err = fault.New("course not found", fault.WithCode(fault.NotFound))
+		}
+		errResponse := web.ToResponse(err)
+		res.Status = errResponse.StatusCode
+		res.Error = &errResponse
+		return res
+	}
+
+	switch model.BatchOperationType(op) {
+	case model.BatchCreate:
+		res.Status = http.StatusCreated
+	case model.BatchDelete:
+		res.Status = http.StatusNoContent
+		return res
+	default:
+		res.Status = http.StatusOK
+	}
+
+	res.Course = &CreateCourseResponse{
+		ID:          result.Course.ID,
+		Title:       result.Course.Title,
+		Description: result.Course.Description,
+		CreatedAt:   result.Course.CreatedAt.String(),
+	}
+	return res
+}
@@ -0,0 +1,101 @@
+//go:build unit
+
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/handler"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/pkg/validator"
+)
+
+type stubBatchService struct {
+	calls [][]model.BatchOperation
+}
+
+func (s *stubBatchService) ExecuteBatch(_ context.Context, _ model.BatchMode, ops []model.BatchOperation) []model.BatchResult {
+	s.calls = append(s.calls, ops)
+	results := make([]model.BatchResult, len(ops))
+	for i, op := range ops {
+		switch op.Type {
+		case model.BatchDelete:
+			results[i] = model.BatchResult{Err: model.ErrCourseNotFound}
+		default:
+			results[i] = model.BatchResult{Course: &model.Course{ID: op.ID, Title: op.Title, CreatedAt: time.Now()}}
+		}
+	}
+	return results
+}
+
+func doBatch(t *testing.T, svc *stubBatchService, body string) (int, handler.BatchCoursesResponse) {
+	t.Helper()
+
+	cfg := &config.ServerConfig{Batch: config.BatchConfig{MaxOperations: 3}}
+	h := handler.NewBatchCoursesHandler(cfg, validator.NewValidator(), svc)
+
+	rec := httptest.NewRecorder()
+	h.Handle(rec, httptest.NewRequest(http.MethodPost, "/api/v1/courses:batch", strings.NewReader(body)))
+
+	var resp handler.BatchCoursesResponse
+	if rec.Code == http.StatusOK || rec.Code == http.StatusMultiStatus {
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	}
+	return rec.Code, resp
+}
+
+func TestBatchCoursesHandler(t *testing.T) {
+	id := "0199a000-0000-7000-8000-00000000000a"
+
+	t.Run("should report per operation status in best effort mode", func(t *testing.T) {
+		svc := &stubBatchService{}
+		code, resp := doBatch(t, svc, `{"mode":"best_effort","operations":[
+			{"op":"create","title":"Go","description":"Go course"},
+			{"op":"update","id":"`+id+`","title":""},
+			{"op":"delete","id":"`+id+`"}
+		]}`)
+
+		assert.Equal(t, http.StatusMultiStatus, code)
+		assert.Equal(t, 1, resp.Succeeded)
+		assert.Equal(t, 2, resp.Failed)
+		require.Len(t, resp.Results, 3)
+		assert.Equal(t, http.StatusCreated, resp.Results[0].Status)
+		assert.Equal(t, http.StatusBadRequest, resp.Results[1].Status)
+		assert.NotEmpty(t, resp.Results[1].Error.Details)
+		assert.Equal(t, http.StatusNotFound, resp.Results[2].Status)
+		assert.Equal(t, "not_found", resp.Results[2].Error.Code)
+		require.Len(t, svc.calls, 1)
+		assert.Len(t, svc.calls[0], 2)
+	})
+
+	t.Run("should not run an atomic batch with an invalid operation", func(t *testing.T) {
+		svc := &stubBatchService{}
+		code, resp := doBatch(t, svc, `{"operations":[
+			{"op":"create","title":"Go","description":"Go course"},
+			{"op":"delete","id":"not-a-uuid"}
+		]}`)
+
+		assert.Equal(t, http.StatusMultiStatus, code)
+		assert.Equal(t, "atomic", resp.Mode)
+		assert.Equal(t, http.StatusConflict, resp.Results[0].Status)
+		assert.Equal(t, http.StatusBadRequest, resp.Results[1].Status)
+		assert.Empty(t, svc.calls)
+	})
+
+	t.Run("should reject batches over the operation limit", func(t *testing.T) {
+		op := `{"op":"delete","id":"` + id + `"}`
+		code, _ := doBatch(t, &stubBatchService{}, `{"operations":[`+strings.Repeat(op+",", 3)+op+`]}`)
+
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/collab"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/pkg/shutdown"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+const (
+	collabWriteWait  = 10 * time.Second
+	collabPongWait   = 60 * time.Second
+	collabPingPeriod = (collabPongWait * 9) / 10
+)
+
+type CourseCollabHandler struct {
+	hub            *collab.Hub
+	upgrader       websocket.Upgrader
+	maxMessageSize int64
+}
+
+func NewCourseCollabHandler(cfg *config.ServerConfig, hub *collab.Hub) *CourseCollabHandler {
+	allowedOrigins := cfg.CORS.AllowedOrigins
+
+	return &CourseCollabHandler{
+		hub: hub,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				return origin == "" || slices.Contains(allowedOrigins, "*") || slices.Contains(allowedOrigins, origin)
+			},
+		},
+		maxMessageSize: cfg.Collab.MaxMessageSize + 1024,
+	}
+}
+
+// Handle godoc
+// @Summary      Edit a course collaboratively
+// @Description  Upgrades to a WebSocket that streams snapshots, edits and presence of the editors of a course. Browsers may pass the token as access_token.
+// @Tags         Courses
+// @Param        id            path   string  true   "Course id"
+// @Param        name          query  string  false  "Name shown to the other editors"
+// @Param        access_token  query  string  false  "JWT, for clients that cannot send headers"
+// @Success      101
+// @Failure      400  {object}  ErrorResponse "Invalid id"
+// @Failure      403  {object}  ErrorResponse "Missing course:update permission"
+// @Failure      404  {object}  ErrorResponse "Course not found"
+// @Security     BearerAuth
+// @Router       /courses/{id}/collab [get]
+func (h *CourseCollabHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := web.GetLogger(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(idStr); err != nil {
+		logger.Warn("invalid uuid format in url param", "id", idStr, "error", err)
+		web.Error(w, r, fault.New("invalid id format, must be a valid uuid", fault.WithCode(fault.Invalid)))
+		return
+	}
+
+	client, err := h.hub.Join(ctx, idStr, r.URL.Query().Get("name"))
+	if err != nil {
+		if errors.Is(err, model.ErrCourseNotFound) {
+			logger.Warn("course not found for collaboration", "id", idStr)
+			web.Error(w, r, fault.New("course not found", fault.WithCode(fault.NotFound)))
+			return
+		}
+
+		logger.Error("failed to join collaboration session", "id", idStr, "error", err)
+		web.Error(w, r, err)
+		return
+	}
+	defer client.Leave()
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Warn("failed to upgrade collaboration connection", "id", idStr, "error", err)
+		return
+	}
+	defer conn.Close()
+
+	logger = logger.With("course_id", idStr, "editor_id", client.Editor().ID)
+	logger.Info("editor joined collaboration session")
+
+	done := make(chan struct{})
+	rejected := make(chan collab.Message, 8)
+
+	go h.readPump(conn, client, rejected, done)
+	h.writePump(conn, client, rejected, done, shutdown.Stopping(ctx))
+
+	logger.Info("editor left collaboration session")
+}
+
+// readPump forwards editor operations to the hub. Rejected operations are
+// handed to writePump, the only goroutine allowed to write to conn.
+func (h *CourseCollabHandler) readPump(
+	conn *websocket.Conn,
+	client *collab.Client,
+	rejected chan<- collab.Message,
+	done chan<- struct{},
+) {
+	defer close(done)
+
+	conn.SetReadLimit(h.maxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(collabPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(collabPongWait))
+	})
+
+	for {
+		var msg collab.Message
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		if err := client.Submit(msg); err != nil {
+			select {
+			case rejected <- collab.Message{Type: collab.MessageError, ErrorMsg: err.Error()}:
+			default:
+			}
+		}
+	}
+}
+
+func (h *CourseCollabHandler) writePump(
+	conn *websocket.Conn,
+	client *collab.Client,
+	rejected <-chan collab.Message,
+	done <-chan struct{},
+	stopping <-chan struct{},
+) {
+	ticker := time.NewTicker(collabPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-stopping:
+			conn.SetWriteDeadline(time.Now().Add(collabWriteWait))
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+			return
+		case msg := <-rejected:
+			conn.SetWriteDeadline(time.Now().Add(collabWriteWait))
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case msg, ok := <-client.Messages():
+			conn.SetWriteDeadline(time.Now().Add(collabWriteWait))
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(collabWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/event"
+	"github.com/marcelofabianov/dojo-go/pkg/shutdown"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type CourseEventsHandler struct {
+	broker            *event.Broker
+	heartbeatInterval time.Duration
+}
+
+const defaultHeartbeatInterval = 15 * time.Second
+
+func NewCourseEventsHandler(cfg *config.ServerConfig, broker *event.Broker) *CourseEventsHandler {
+	heartbeatInterval := cfg.Events.HeartbeatInterval
+	if heartbeatInterval <= 0 {
+		heartbeatInterval = defaultHeartbeatInterval
+	}
+
+	return &CourseEventsHandler{
+		broker:            broker,
+		heartbeatInterval: heartbeatInterval,
+	}
+}
+
+// Handle godoc
+// @Summary      Stream course changes
+// @Description  Streams course create/update/delete notifications as Server-Sent Events.
+// @Tags         Courses
+// @Produce      text/event-stream
+// @Param        course_id      query   string  false  "Comma separated course ids to filter by"
+// @Param        Last-Event-ID  header  string  false  "Resume after this event id"
+// @Success      200
+// @Failure      400  {object}  ErrorResponse "Invalid filter or event id"
+// @Router       /courses/events [get]
+func (h *CourseEventsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := web.GetLogger(ctx)
+
+	courseIDs, err := parseCourseIDsFilter(r.URL.Query().Get("course_id"))
+	if err != nil {
+		logger.Warn("invalid course id filter", "error", err)
+		web.Error(w, r, err)
+		return
+	}
+
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		logger.Warn("invalid last event id", "error", err)
+		web.Error(w, r, err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		logger.Warn("failed to clear write deadline for event stream", "error", err)
+	}
+
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		logger.Error("course event stream without tenant", "error", err)
+		web.Error(w, r, err)
+		return
+	}
+
+	sub, replay := h.broker.Subscribe(tenantID, lastEventID, courseIDs)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, e := range replay {
+		if err := writeCourseEvent(w, e); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		logger.Error("event stream does not support flushing", "error", err)
+		return
+	}
+
+	logger.Info("course event stream opened", "last_event_id", lastEventID, "replayed", len(replay))
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	stopping := shutdown.Stopping(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("course event stream closed by client")
+			return
+		case <-stopping:
+			// EventSource reconnects with Last-Event-ID, landing on another
+			// instance without losing events.
+			logger.Info("course event stream closed by server shutdown")
+			return
+		case e, ok := <-sub.Events():
+			if !ok {
+				logger.Warn("course event stream dropped by broker")
+				return
+			}
+			if err := writeCourseEvent(w, e); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeCourseEvent(w http.ResponseWriter, e event.CourseEvent) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
+
+func parseCourseIDsFilter(raw string) ([]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	ids := strings.Split(raw, ",")
+	for i, id := range ids {
+		id = strings.TrimSpace(id)
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, fault.New("invalid course_id filter, must be a comma separated list of uuids",
+				fault.WithCode(fault.Invalid),
+				fault.WithContext("course_id", id),
+			)
+		}
+		ids[i] = id
+	}
+
+	return ids, nil
+}
+
+// parseLastEventID reads the Last-Event-ID header sent by EventSource on
+// reconnect, falling back to a last_event_id query param for the first
+// connection, where browsers cannot set headers.
+func parseLastEventID(r *http.Request) (uint64, error) {
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = r.URL.Query().Get("last_event_id")
+	}
+	if raw == "" {
+		return 0, nil
+	}
+
+	// Event ids start at 1, so 0 is as invalid as a negative id.
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil || id == 0 {
+		return 0, fault.New("invalid Last-Event-ID, must be a positive integer",
+			fault.WithCode(fault.Invalid),
+		)
+	}
+
+	return id, nil
+}
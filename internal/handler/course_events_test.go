@@ -0,0 +1,61 @@
+//go:build unit
+
+package handler_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/event"
+	"github.com/marcelofabianov/dojo-go/internal/handler"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+func TestCourseEventsHandler(t *testing.T) {
+	cfg := &config.ServerConfig{Events: config.EventsConfig{ReplayBufferSize: 10}}
+	broker := event.NewBroker(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	for id := uint64(1); id <= 3; id++ {
+		broker.Publish(event.CourseEvent{ID: id, Type: event.CourseUpdated, CourseID: "c1", TenantID: "school-a", OccurredAt: time.Now()})
+	}
+	h := handler.NewCourseEventsHandler(cfg, broker)
+
+	// stream serves a request whose client is already gone, so the handler
+	// returns right after the replay.
+	stream := func(lastEventID string) *httptest.ResponseRecorder {
+		ctx, cancel := context.WithCancel(tenant.WithID(context.Background(), "school-a"))
+		cancel()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/courses/events", nil).WithContext(ctx)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		rec := httptest.NewRecorder()
+		h.Handle(rec, req)
+		return rec
+	}
+
+	t.Run("should replay the events after the last event id", func(t *testing.T) {
+		rec := stream("2")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "id: 3\n")
+		assert.NotContains(t, rec.Body.String(), "id: 2\n")
+	})
+
+	for _, raw := range []string{"0", "-1", "abc"} {
+		t.Run("should reject last event id "+raw, func(t *testing.T) {
+			rec := stream(raw)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), "must be a positive integer")
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/validator"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=255"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type APIKeyResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Prefix      string   `json:"prefix"`
+	Scopes      []string `json:"scopes"`
+	ExpiresAt   *string  `json:"expires_at,omitempty"`
+	LastUsedAt  *string  `json:"last_used_at,omitempty"`
+	RevokedAt   *string  `json:"revoked_at,omitempty"`
+	RotatedFrom *string  `json:"rotated_from,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// CreateAPIKeyResponse carries the full key, which is only ever shown once.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type CreateAPIKeyHandler struct {
+	validator     *validator.Validator
+	apiKeyService port.APIKeyServicePort
+}
+
+func NewCreateAPIKeyHandler(validator *validator.Validator, apiKeyService port.APIKeyServicePort) *CreateAPIKeyHandler {
+	return &CreateAPIKeyHandler{
+		validator:     validator,
+		apiKeyService: apiKeyService,
+	}
+}
+
+// Handle godoc
+// @Summary      Create an API key
+// @Description  Creates an API key for a machine client. The key is only returned in this response.
+// @Tags         API Keys
+// @Accept       json
+// @Produce      json
+// @Param        apiKey  body      CreateAPIKeyRequest  true  "API key data"
+// @Success      201     {object}  CreateAPIKeyResponse
+// @Failure      400     {object}  ErrorResponse "Validation errors"
+// @Failure      403     {object}  ErrorResponse "Missing apikey:manage permission"
+// @Security     BearerAuth
+// @Router       /api-keys [post]
+func (h *CreateAPIKeyHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := web.GetLogger(ctx)
+
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", "error", err)
+		web.ErrDecodeRequestBody(err, w, r)
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		logger.Warn("request validation failed", "error", err)
+		web.Error(w, r, err)
+		return
+	}
+
+	key, token, err := h.apiKeyService.CreateAPIKey(ctx, model.NewAPIKeyInput{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		logger.Error("failed to create api key", "error", err)
+		web.Error(w, r, err)
+		return
+	}
+
+	logger.Info("api key created successfully", "api_key_id", key.ID, "api_key_prefix", key.Prefix)
+	web.Success(w, r, http.StatusCreated, CreateAPIKeyResponse{
+		APIKeyResponse: newAPIKeyResponse(key),
+		Key:            token,
+	})
+}
+
+func newAPIKeyResponse(key *model.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:          key.ID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		Scopes:      key.Scopes,
+		ExpiresAt:   formatOptionalTime(key.ExpiresAt),
+		LastUsedAt:  formatOptionalTime(key.LastUsedAt),
+		RevokedAt:   formatOptionalTime(key.RevokedAt),
+		RotatedFrom: key.RotatedFrom,
+		CreatedAt:   key.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format(time.RFC3339)
+	return &formatted
+}
@@ -45,9 +45,12 @@ func NewCreateCourseHandler(
 // @Tags         Courses
 // @Accept       json
 // @Produce      json
-// @Param        course  body      CreateCourseRequest  true  "Course creation data"
+// @Param        course           body      CreateCourseRequest  true   "Course creation data"
+// @Param        Idempotency-Key  header    string               false  "Makes retries of this request safe"
 // @Success      201     {object}  CreateCourseResponse
 // @Failure      400     {object}  ErrorResponse "Validation errors"
+// @Failure      409     {object}  ErrorResponse "Request with the same idempotency key still in progress"
+// @Failure      422     {object}  ErrorResponse "Idempotency key reused with a different payload"
 // @Failure      500     {object}  ErrorResponse "Internal server error"
 // @Router       /courses [post]
 func (h *CreateCourseHandler) Handle(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type DiffCourseRevisionsResponse struct {
+	From    int                `json:"from"`
+	To      int                `json:"to"`
+	Changes model.FieldChanges `json:"changes"`
+}
+
+type DiffCourseRevisionsHandler struct {
+	courseService port.CourseServicePort
+}
+
+func NewDiffCourseRevisionsHandler(courseService port.CourseServicePort) *DiffCourseRevisionsHandler {
+	return &DiffCourseRevisionsHandler{
+		courseService: courseService,
+	}
+}
+
+// Handle godoc
+// @Summary      Diff two course revisions
+// @Description  Returns the fields that changed from one revision to another. Unchanged fields are omitted.
+// @Tags         Courses
+// @Produce      json
+// @Param        id    path      string  true  "Course id"
+// @Param        from  query     int     true  "Base revision"
+// @Param        to    query     int     true  "Target revision"
+// @Success      200   {object}  DiffCourseRevisionsResponse
+// @Failure      400   {object}  ErrorResponse "Invalid id or revision"
+// @Failure      404   {object}  ErrorResponse "Revision not found"
+// @Security     BearerAuth
+// @Router       /courses/{id}/revisions/diff [get]
+func (h *DiffCourseRevisionsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := web.GetLogger(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(idStr); err != nil {
+		logger.Warn("invalid uuid format in url param", "id", idStr, "error", err)
+		web.Error(w, r, fault.New("invalid id format, must be a valid uuid", fault.WithCode(fault.Invalid)))
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := parseRevision("from", query.Get("from"))
+	if err != nil {
+		web.Error(w, r, err)
+		return
+	}
+	to, err := parseRevision("to", query.Get("to"))
+	if err != nil {
+		web.Error(w, r, err)
+		return
+	}
+
+	changes, err := h.courseService.DiffCourseRevisions(ctx, idStr, from, to)
+	if err != nil {
+		logger.Warn("failed to diff course revisions", "id", idStr, "from", from, "to", to, "error", err)
+		web.Error(w, r, revisionError(err))
+		return
+	}
+
+	web.Success(w, r, http.StatusOK, DiffCourseRevisionsResponse{From: from, To: to, Changes: changes})
+}
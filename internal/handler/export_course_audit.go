@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+var auditCSVHeader = []string{"id", "course_id", "action", "actor", "request_id", "occurred_at", "before", "after", "changes", "co_actors"}
+
+type ExportCourseAuditHandler struct {
+	auditService port.AuditServicePort
+}
+
+func NewExportCourseAuditHandler(auditService port.AuditServicePort) *ExportCourseAuditHandler {
+	return &ExportCourseAuditHandler{
+		auditService: auditService,
+	}
+}
+
+// Handle godoc
+// @Summary      Export course audit entries
+// @Description  Streams every audit entry matching the filters as CSV or newline delimited JSON, for compliance archiving.
+// @Tags         Audit
+// @Produce      text/csv,application/x-ndjson
+// @Param        format     query  string  false  "csv (default) or ndjson"
+// @Param        course_id  query  string  false  "Course id"
+// @Param        actor      query  string  false  "Actor"
+// @Param        from       query  string  false  "Entries at or after this RFC 3339 time"
+// @Param        to         query  string  false  "Entries before this RFC 3339 time"
+// @Success      200
+// @Failure      400  {object}  ErrorResponse "Invalid filter or format"
+// @Failure      403  {object}  ErrorResponse "Missing audit:read permission"
+// @Security     BearerAuth
+// @Router       /audit/courses/export [get]
+func (h *ExportCourseAuditHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := web.GetLogger(ctx)
+
+	filter, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		logger.Warn("invalid audit filter", "error", err)
+		web.Error(w, r, err)
+		return
+	}
+	filter.Cursor = ""
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "ndjson" {
+		web.Error(w, r, fault.New("invalid format, must be csv or ndjson",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("format", format),
+		))
+		return
+	}
+
+	// Exports can outlive the server write timeout.
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		logger.Warn("failed to clear write deadline for audit export", "error", err)
+	}
+
+	// Headers are only sent with the first entry, so an authorization or
+	// database error before it still gets a regular JSON error response.
+	started := false
+	start := func(contentType string) {
+		if started {
+			return
+		}
+		started = true
+		filename := fmt.Sprintf("course-audit-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format)
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		w.WriteHeader(http.StatusOK)
+	}
+
+	var write func(*model.AuditEntry) error
+	var flush func() error
+
+	switch format {
+	case "csv":
+		cw := csv.NewWriter(w)
+		headerWritten := false
+		writeRow := func(record []string) error {
+			start("text/csv; charset=utf-8")
+			if !headerWritten {
+				headerWritten = true
+				if err := cw.Write(auditCSVHeader); err != nil {
+					return err
+				}
+			}
+			if record != nil {
+				if err := cw.Write(record); err != nil {
+					return err
+				}
+			}
+			cw.Flush()
+			return cw.Error()
+		}
+		write = func(entry *model.AuditEntry) error {
+			record, err := auditCSVRecord(entry)
+			if err != nil {
+				return err
+			}
+			return writeRow(record)
+		}
+		flush = func() error {
+			return writeRow(nil)
+		}
+	case "ndjson":
+		enc := json.NewEncoder(w)
+		write = func(entry *model.AuditEntry) error {
+			start("application/x-ndjson")
+			return enc.Encode(newAuditEntryResponse(entry))
+		}
+		flush = func() error {
+			start("application/x-ndjson")
+			return nil
+		}
+	}
+
+	exported := 0
+	err = h.auditService.ExportAuditEntries(ctx, filter, func(entry *model.AuditEntry) error {
+		exported++
+		return write(entry)
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		logger.Error("failed to export audit entries", "exported", exported, "error", err)
+		if !started {
+			web.Error(w, r, err)
+		}
+		return
+	}
+
+	logger.Info("audit entries exported", "format", format, "exported", exported)
+}
+
+func auditCSVRecord(entry *model.AuditEntry) ([]string, error) {
+	encode := func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	}
+
+	before, err := encode(entry.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := encode(entry.After)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := encode(entry.Changes)
+	if err != nil {
+		return nil, err
+	}
+	coActors, err := encode(entry.CoActors)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{
+		entry.ID,
+		entry.CourseID,
+		string(entry.Action),
+		entry.Actor,
+		entry.RequestID,
+		entry.OccurredAt.Format(time.RFC3339Nano),
+		before,
+		after,
+		changes,
+		coActors,
+	}, nil
+}
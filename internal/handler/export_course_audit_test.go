@@ -0,0 +1,108 @@
+//go:build unit
+
+package handler_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/internal/handler"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+)
+
+type stubAuditService struct {
+	entries []*model.AuditEntry
+	err     error
+	filter  model.AuditFilter
+}
+
+func (s *stubAuditService) ListAuditEntries(_ context.Context, filter model.AuditFilter) ([]*model.AuditEntry, string, error) {
+	s.filter = filter
+	return s.entries, "", s.err
+}
+
+func (s *stubAuditService) ExportAuditEntries(_ context.Context, filter model.AuditFilter, fn func(*model.AuditEntry) error) error {
+	s.filter = filter
+	if s.err != nil {
+		return s.err
+	}
+	for _, entry := range s.entries {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestExportCourseAuditHandler(t *testing.T) {
+	courseID := "0199a000-0000-7000-8000-00000000000a"
+	title := "Go"
+	entries := []*model.AuditEntry{{
+		ID:         "0199a000-0000-7000-8000-0000000000e1",
+		CourseID:   courseID,
+		Action:     model.AuditCourseCreated,
+		Actor:      "user-1",
+		After:      &model.CourseSnapshot{ID: courseID, Title: title},
+		Changes:    model.FieldChanges{"title": {To: &title}},
+		OccurredAt: time.Now(),
+	}}
+
+	export := func(svc *stubAuditService, query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.NewExportCourseAuditHandler(svc).Handle(rec, httptest.NewRequest(http.MethodGet, "/api/v1/audit/courses/export"+query, nil))
+		return rec
+	}
+
+	t.Run("should export csv with a header row", func(t *testing.T) {
+		svc := &stubAuditService{entries: entries}
+		rec := export(svc, "?actor=user-1&from=2025-10-01T00:00:00Z")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+		assert.Equal(t, "user-1", svc.filter.Actor)
+		require.NotNil(t, svc.filter.From)
+
+		records, err := csv.NewReader(rec.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "id", records[0][0])
+		assert.Equal(t, courseID, records[1][1])
+		assert.JSONEq(t, `{"title":{"from":null,"to":"Go"}}`, records[1][8])
+	})
+
+	t.Run("should export ndjson", func(t *testing.T) {
+		rec := export(&stubAuditService{entries: entries}, "?format=ndjson")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		require.Len(t, lines, 1)
+
+		var entry handler.AuditEntryResponse
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+		assert.Equal(t, "course.created", entry.Action)
+	})
+
+	t.Run("should reject invalid filters", func(t *testing.T) {
+		for _, query := range []string{"?format=xml", "?from=yesterday", "?course_id=1", "?limit=-1"} {
+			rec := export(&stubAuditService{}, query)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+
+	t.Run("should return a json error when the export cannot start", func(t *testing.T) {
+		rec := export(&stubAuditService{err: fault.New("denied", fault.WithCode(fault.Forbidden))}, "")
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Contains(t, rec.Header().Get("Content-Type"), "application/json")
+	})
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type GetCourseRevisionHandler struct {
+	courseService port.CourseServicePort
+}
+
+func NewGetCourseRevisionHandler(courseService port.CourseServicePort) *GetCourseRevisionHandler {
+	return &GetCourseRevisionHandler{
+		courseService: courseService,
+	}
+}
+
+// Handle godoc
+// @Summary      Get a course revision
+// @Tags         Courses
+// @Produce      json
+// @Param        id        path      string  true  "Course id"
+// @Param        revision  path      int     true  "Revision number"
+// @Success      200       {object}  CourseRevisionResponse
+// @Failure      400       {object}  ErrorResponse "Invalid id or revision"
+// @Failure      404       {object}  ErrorResponse "Revision not found"
+// @Security     BearerAuth
+// @Router       /courses/{id}/revisions/{revision} [get]
+func (h *GetCourseRevisionHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := web.GetLogger(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(idStr); err != nil {
+		logger.Warn("invalid uuid format in url param", "id", idStr, "error", err)
+		web.Error(w, r, fault.New("invalid id format, must be a valid uuid", fault.WithCode(fault.Invalid)))
+		return
+	}
+
+	number, err := parseRevision("revision", chi.URLParam(r, "revision"))
+	if err != nil {
+		web.Error(w, r, err)
+		return
+	}
+
+	revision, err := h.courseService.GetCourseRevision(ctx, idStr, number)
+	if err != nil {
+		logger.Warn("failed to get course revision", "id", idStr, "revision", number, "error", err)
+		web.Error(w, r, revisionError(err))
+		return
+	}
+
+	web.Success(w, r, http.StatusOK, newCourseRevisionResponse(revision))
+}
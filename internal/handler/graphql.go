@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/validator"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type GraphQLHandler struct {
+	schema        graphql.Schema
+	courseService port.CourseServicePort
+	maxDepth      int
+	maxComplexity int
+}
+
+func NewGraphQLHandler(
+	cfg *config.ServerConfig,
+	validator *validator.Validator,
+	courseService port.CourseServicePort,
+) (*GraphQLHandler, error) {
+	schema, err := newGraphQLSchema(validator, courseService)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GraphQLHandler{
+		schema:        schema,
+		courseService: courseService,
+		maxDepth:      cfg.GraphQL.MaxDepth,
+		maxComplexity: cfg.GraphQL.MaxComplexity,
+	}, nil
+}
+
+// Handle godoc
+// @Summary      GraphQL endpoint
+// @Description  Executes GraphQL queries and mutations over courses.
+// @Tags         GraphQL
+// @Accept       json
+// @Produce      json
+// @Param        request  body      GraphQLRequest  true  "GraphQL request"
+// @Success      200      {object}  map[string]any
+// @Failure      400      {object}  ErrorResponse "Malformed request body"
+// @Router       /graphql [post]
+func (h *GraphQLHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := web.GetLogger(ctx)
+
+	var req GraphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Error("failed to decode request body", "error", err)
+		web.ErrDecodeRequestBody(err, w, r)
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		logger.Warn("failed to parse graphql query", "error", err)
+		web.Success(w, r, http.StatusOK, &graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+		return
+	}
+
+	if validation := graphql.ValidateDocument(&h.schema, doc, nil); !validation.IsValid {
+		logger.Warn("graphql query validation failed", "errors", len(validation.Errors))
+		web.Success(w, r, http.StatusOK, &graphql.Result{Errors: validation.Errors})
+		return
+	}
+
+	if err := checkQueryLimits(doc, req.OperationName, req.Variables, h.maxDepth, h.maxComplexity); err != nil {
+		logger.Warn("graphql query rejected by limits", "error", err)
+		web.Success(w, r, http.StatusOK, &graphql.Result{Errors: []gqlerrors.FormattedError{formatGraphQLError(err)}})
+		return
+	}
+
+	loaderCtx := withCourseLoader(ctx, newCourseLoader(ctx, h.courseService))
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       loaderCtx,
+	})
+
+	withExtensions(result.Errors)
+
+	if result.HasErrors() {
+		logger.Warn("graphql request completed with errors", "errors", len(result.Errors))
+	} else {
+		logger.Info("graphql request completed successfully", "operation", req.OperationName)
+	}
+
+	web.Success(w, r, http.StatusOK, result)
+}
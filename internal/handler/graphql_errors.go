@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+// graphQLError exposes a fault as a GraphQL error, carrying the same code,
+// status, context and details the REST API returns under "extensions".
+type graphQLError struct {
+	response fault.ErrorResponse
+}
+
+func (e graphQLError) Error() string {
+	return e.response.Message
+}
+
+func (e graphQLError) Extensions() map[string]any {
+	extensions := map[string]any{
+		"code":   e.response.Code,
+		"status": e.response.StatusCode,
+	}
+	if len(e.response.Context) > 0 {
+		extensions["context"] = e.response.Context
+	}
+	if len(e.response.Details) > 0 {
+		extensions["details"] = e.response.Details
+	}
+	return extensions
+}
+
+func toGraphQLError(err error) error {
+	if errors.Is(err, model.ErrCourseNotFound) {
+		err = fault.New("course not found", fault.WithCode(fault.NotFound))
+	}
+	return graphQLError{response: web.ToResponse(err)}
+}
+
+// withExtensions fills in the extensions of errors returned from thunks.
+// graphql-go formats those errors before adding the location and path,
+// which hides the graphQLError and its extensions behind two wrappers.
+func withExtensions(errs []gqlerrors.FormattedError) {
+	for i, formatted := range errs {
+		if formatted.Extensions != nil {
+			continue
+		}
+
+		err := formatted.OriginalError()
+		for err != nil {
+			if extended, ok := err.(gqlerrors.ExtendedError); ok {
+				errs[i].Extensions = extended.Extensions()
+				break
+			}
+
+			switch wrapped := err.(type) {
+			case *gqlerrors.Error:
+				err = wrapped.OriginalError
+			case gqlerrors.FormattedError:
+				err = wrapped.OriginalError()
+			default:
+				err = nil
+			}
+		}
+	}
+}
+
+// formatGraphQLError is used for errors raised outside execution, where
+// graphql-go would not read the extensions on its own.
+func formatGraphQLError(err error) gqlerrors.FormattedError {
+	gqlErr := toGraphQLError(err).(graphQLError)
+	return gqlerrors.FormattedError{
+		Message:    gqlErr.Error(),
+		Locations:  []location.SourceLocation{},
+		Extensions: gqlErr.Extensions(),
+	}
+}
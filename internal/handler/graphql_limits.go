@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/marcelofabianov/fault"
+)
+
+// queryCost walks the selected operation, following fragments, and returns
+// its maximum selection depth and complexity. Every field costs one point and
+// list fields multiply the cost of their selection by the number of ids they
+// receive. Introspection fields are not counted.
+type queryCost struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+}
+
+func analyzeQuery(doc *ast.Document, operationName string, variables map[string]any) (depth, complexity int) {
+	qc := queryCost{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+	}
+
+	var operation *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			qc.fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			if operation == nil || (def.Name != nil && def.Name.Value == operationName) {
+				operation = def
+			}
+		}
+	}
+
+	if operation == nil {
+		return 0, 0
+	}
+
+	return qc.selectionSet(operation.SelectionSet, map[string]bool{})
+}
+
+func (qc queryCost) selectionSet(set *ast.SelectionSet, visiting map[string]bool) (depth, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, c int
+
+		switch sel := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name.Value, "__") {
+				continue
+			}
+			d, c = qc.selectionSet(sel.SelectionSet, visiting)
+			d, c = d+1, (c+1)*qc.multiplier(sel)
+		case *ast.InlineFragment:
+			d, c = qc.selectionSet(sel.SelectionSet, visiting)
+		case *ast.FragmentSpread:
+			name := sel.Name.Value
+			fragment, ok := qc.fragments[name]
+			if !ok || visiting[name] {
+				continue
+			}
+			visiting[name] = true
+			d, c = qc.selectionSet(fragment.SelectionSet, visiting)
+			delete(visiting, name)
+		}
+
+		depth = max(depth, d)
+		complexity += c
+	}
+
+	return depth, complexity
+}
+
+func (qc queryCost) multiplier(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "ids" {
+			continue
+		}
+
+		switch value := arg.Value.(type) {
+		case *ast.ListValue:
+			return max(len(value.Values), 1)
+		case *ast.Variable:
+			if ids, ok := qc.variables[value.Name.Value].([]any); ok {
+				return max(len(ids), 1)
+			}
+		}
+	}
+
+	return 1
+}
+
+func checkQueryLimits(doc *ast.Document, operationName string, variables map[string]any, maxDepth, maxComplexity int) error {
+	depth, complexity := analyzeQuery(doc, operationName, variables)
+
+	if maxDepth > 0 && depth > maxDepth {
+		return fault.New("query exceeds the maximum allowed depth",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("depth", depth),
+			fault.WithContext("max_depth", maxDepth),
+		)
+	}
+
+	if maxComplexity > 0 && complexity > maxComplexity {
+		return fault.New("query exceeds the maximum allowed complexity",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("complexity", complexity),
+			fault.WithContext("max_complexity", maxComplexity),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"sync"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+)
+
+type courseLoaderCtxKey struct{}
+
+type courseResult struct {
+	done   chan struct{}
+	course *model.Course
+	err    error
+}
+
+// courseLoader is a per-request DataLoader for GetCoursesByIDs. Loads return
+// a thunk; graphql-go resolves every thunk of a level only after all of them
+// were created, so the first thunk fetches the whole batch of pending ids in
+// one call. Results are cached for the request, so each id is fetched at most
+// once.
+type courseLoader struct {
+	ctx           context.Context
+	courseService port.CourseServicePort
+	mu            sync.Mutex
+	pending       []string
+	cache         map[string]*courseResult
+}
+
+func newCourseLoader(ctx context.Context, courseService port.CourseServicePort) *courseLoader {
+	return &courseLoader{
+		ctx:           ctx,
+		courseService: courseService,
+		cache:         make(map[string]*courseResult),
+	}
+}
+
+func withCourseLoader(ctx context.Context, loader *courseLoader) context.Context {
+	return context.WithValue(ctx, courseLoaderCtxKey{}, loader)
+}
+
+func getCourseLoader(ctx context.Context) *courseLoader {
+	return ctx.Value(courseLoaderCtxKey{}).(*courseLoader)
+}
+
+func (l *courseLoader) Load(id string) func() (*model.Course, error) {
+	l.mu.Lock()
+	res, ok := l.cache[id]
+	if !ok {
+		res = &courseResult{done: make(chan struct{})}
+		l.cache[id] = res
+		l.pending = append(l.pending, id)
+	}
+	l.mu.Unlock()
+
+	return func() (*model.Course, error) {
+		l.dispatch()
+		<-res.done
+		return res.course, res.err
+	}
+}
+
+// Prime stores a course already known by the caller, e.g. returned by a
+// mutation, so later loads of the same id do not hit the service.
+func (l *courseLoader) Prime(course *model.Course) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.cache[course.ID]; ok {
+		return
+	}
+
+	res := &courseResult{done: make(chan struct{}), course: course}
+	close(res.done)
+	l.cache[course.ID] = res
+}
+
+func (l *courseLoader) dispatch() {
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	results := make([]*courseResult, len(keys))
+	for i, key := range keys {
+		results[i] = l.cache[key]
+	}
+	l.mu.Unlock()
+
+	if len(keys) == 0 {
+		return
+	}
+
+	courses, err := l.courseService.GetCoursesByIDs(l.ctx, keys)
+	byID := make(map[string]*model.Course, len(courses))
+	for _, course := range courses {
+		byID[course.ID] = course
+	}
+
+	for i, key := range keys {
+		res := results[i]
+		switch course, ok := byID[key]; {
+		case err != nil:
+			res.err = err
+		case !ok:
+			res.err = model.ErrCourseNotFound
+		default:
+			res.course = course
+		}
+		close(res.done)
+	}
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/validator"
+)
+
+type graphQLResolver struct {
+	validator     *validator.Validator
+	courseService port.CourseServicePort
+}
+
+func newGraphQLSchema(validator *validator.Validator, courseService port.CourseServicePort) (graphql.Schema, error) {
+	res := &graphQLResolver{
+		validator:     validator,
+		courseService: courseService,
+	}
+
+	courseType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Course",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"title":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"description": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"createdAt": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(*model.Course).CreatedAt.Format(time.RFC3339Nano), nil
+				},
+			},
+		},
+	})
+
+	courseInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CourseInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":       &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"description": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	idArg := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"course": &graphql.Field{
+				Type:    courseType,
+				Args:    idArg,
+				Resolve: res.course,
+			},
+			"courses": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(courseType)),
+				Args: graphql.FieldConfigArgument{
+					"ids": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID)))},
+				},
+				Resolve: res.courses,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createCourse": &graphql.Field{
+				Type: graphql.NewNonNull(courseType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(courseInputType)},
+				},
+				Resolve: res.createCourse,
+			},
+			"updateCourse": &graphql.Field{
+				Type: graphql.NewNonNull(courseType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(courseInputType)},
+				},
+				Resolve: res.updateCourse,
+			},
+			"deleteCourse": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Boolean),
+				Args:    idArg,
+				Resolve: res.deleteCourse,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+func (res *graphQLResolver) course(p graphql.ResolveParams) (any, error) {
+	id, err := courseIDArg(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	return courseThunk(getCourseLoader(p.Context).Load(id)), nil
+}
+
+func (res *graphQLResolver) courses(p graphql.ResolveParams) (any, error) {
+	rawIDs, _ := p.Args["ids"].([]any)
+	loader := getCourseLoader(p.Context)
+
+	thunks := make([]any, 0, len(rawIDs))
+	for _, raw := range rawIDs {
+		id, err := courseIDArg(raw)
+		if err != nil {
+			return nil, err
+		}
+		thunks = append(thunks, courseThunk(loader.Load(id)))
+	}
+
+	return thunks, nil
+}
+
+func (res *graphQLResolver) createCourse(p graphql.ResolveParams) (any, error) {
+	input, _ := p.Args["input"].(map[string]any)
+	req := CreateCourseRequest{
+		Title:       stringArg(input["title"]),
+		Description: stringArg(input["description"]),
+	}
+
+	if err := res.validator.Validate(req); err != nil {
+		return nil, toGraphQLError(err)
+	}
+
+	course, err := res.courseService.CreateCourse(p.Context, model.NewCourseInput{
+		Title:       req.Title,
+		Description: req.Description,
+	})
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+
+	getCourseLoader(p.Context).Prime(course)
+	return course, nil
+}
+
+func (res *graphQLResolver) updateCourse(p graphql.ResolveParams) (any, error) {
+	id, err := courseIDArg(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	input, _ := p.Args["input"].(map[string]any)
+	req := UpdateCourseRequest{
+		Title:       stringArg(input["title"]),
+		Description: stringArg(input["description"]),
+	}
+
+	if err := res.validator.Validate(req); err != nil {
+		return nil, toGraphQLError(err)
+	}
+
+	course, err := res.courseService.UpdateCourse(p.Context, id, model.UpdateCourseInput{
+		Title:       req.Title,
+		Description: req.Description,
+	})
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+
+	return course, nil
+}
+
+func (res *graphQLResolver) deleteCourse(p graphql.ResolveParams) (any, error) {
+	id, err := courseIDArg(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	if err := res.courseService.DeleteCourseByID(p.Context, id); err != nil {
+		return nil, toGraphQLError(err)
+	}
+
+	return true, nil
+}
+
+// courseThunk adapts a loader result to the thunk signature graphql-go
+// expects.
+func courseThunk(load func() (*model.Course, error)) func() (any, error) {
+	return func() (any, error) {
+		course, err := load()
+		if err != nil {
+			return nil, toGraphQLError(err)
+		}
+		return course, nil
+	}
+}
+
+func courseIDArg(raw any) (string, error) {
+	id := stringArg(raw)
+	if _, err := uuid.Parse(id); err != nil {
+		return "", toGraphQLError(fault.New("invalid id format, must be a valid uuid",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("id", id),
+		))
+	}
+	return id, nil
+}
+
+func stringArg(raw any) string {
+	s, _ := raw.(string)
+	return s
+}
@@ -0,0 +1,155 @@
+//go:build unit
+
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/handler"
+	"github.com/marcelofabianov/dojo-go/internal/mocks"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/service"
+	"github.com/marcelofabianov/dojo-go/pkg/validator"
+)
+
+type graphQLResponse struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func doGraphQL(t *testing.T, h *handler.GraphQLHandler, query string, variables map[string]any) graphQLResponse {
+	t.Helper()
+
+	body, err := json.Marshal(handler.GraphQLRequest{Query: query, Variables: variables})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	h.Handle(rec, httptest.NewRequest(http.MethodPost, "/api/v1/graphql", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp graphQLResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	return resp
+}
+
+func setupGraphQL(t *testing.T) (*handler.GraphQLHandler, *mocks.MockCourseRepository) {
+	t.Helper()
+
+	repo := new(mocks.MockCourseRepository)
+	cfg := &config.ServerConfig{GraphQL: config.GraphQLConfig{MaxDepth: 3, MaxComplexity: 20}}
+	h, err := handler.NewGraphQLHandler(cfg, validator.NewValidator(), service.NewCourseService(repo, mocks.NewCourseRevisionRepositoryStub(), mocks.NewAuditRepositoryStub(), &mocks.FakeTransactor{}, &mocks.FakeMetrics{}, authz.NewPolicy(&config.AuthzConfig{})))
+	require.NoError(t, err)
+
+	return h, repo
+}
+
+// sameIDs matches a batch of ids in any order: graphql-go resolves sibling
+// fields in no fixed order.
+func sameIDs(ids ...string) any {
+	want := slices.Sorted(slices.Values(ids))
+	return mock.MatchedBy(func(got []string) bool {
+		return slices.Equal(want, slices.Sorted(slices.Values(got)))
+	})
+}
+
+func TestGraphQLHandler(t *testing.T) {
+	idA := "0199a000-0000-7000-8000-00000000000a"
+	idB := "0199a000-0000-7000-8000-00000000000b"
+
+	t.Run("should batch and deduplicate course loads", func(t *testing.T) {
+		h, repo := setupGraphQL(t)
+		repo.On("GetCoursesByIDs", mock.Anything, sameIDs(idA, idB)).Return([]*model.Course{
+			{ID: idB, Title: "B", CreatedAt: time.Now()},
+			{ID: idA, Title: "A", CreatedAt: time.Now()},
+		}, nil).Once()
+
+		resp := doGraphQL(t, h, `query($ids: [ID!]!) { courses(ids: $ids) { id title } first: course(id: "`+idA+`") { title } }`,
+			map[string]any{"ids": []any{idA, idB, idA}})
+
+		require.Empty(t, resp.Errors)
+		courses := resp.Data["courses"].([]any)
+		require.Len(t, courses, 3)
+		assert.Equal(t, "B", courses[1].(map[string]any)["title"])
+		assert.Equal(t, "A", resp.Data["first"].(map[string]any)["title"])
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should map faults to graphql error extensions", func(t *testing.T) {
+		h, repo := setupGraphQL(t)
+		repo.On("GetCoursesByIDs", mock.Anything, sameIDs(idA)).Return([]*model.Course{}, nil)
+
+		resp := doGraphQL(t, h, `{ course(id: "`+idA+`") { id } }`, nil)
+
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "course not found", resp.Errors[0].Message)
+		assert.Equal(t, "not_found", resp.Errors[0].Extensions["code"])
+		assert.EqualValues(t, http.StatusNotFound, resp.Errors[0].Extensions["status"])
+	})
+
+	t.Run("should return the other courses when one fails to load", func(t *testing.T) {
+		h, repo := setupGraphQL(t)
+		repo.On("GetCoursesByIDs", mock.Anything, sameIDs(idA, idB)).Return([]*model.Course{
+			{ID: idA, Title: "A", CreatedAt: time.Now()},
+		}, nil)
+
+		resp := doGraphQL(t, h, `{ a: course(id: "`+idA+`") { title } b: course(id: "`+idB+`") { title } }`, nil)
+
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "not_found", resp.Errors[0].Extensions["code"])
+		assert.Equal(t, "A", resp.Data["a"].(map[string]any)["title"])
+		assert.Nil(t, resp.Data["b"])
+	})
+
+	t.Run("should fail every course of a batch that fails to load", func(t *testing.T) {
+		h, repo := setupGraphQL(t)
+		repo.On("GetCoursesByIDs", mock.Anything, sameIDs(idA, idB)).Return(nil, fault.New("database is down", fault.WithCode(fault.Internal)))
+
+		resp := doGraphQL(t, h, `{ a: course(id: "`+idA+`") { title } b: course(id: "`+idB+`") { title } }`, nil)
+
+		require.Len(t, resp.Errors, 2)
+		assert.Nil(t, resp.Data["a"])
+		assert.Nil(t, resp.Data["b"])
+		repo.AssertNumberOfCalls(t, "GetCoursesByIDs", 1)
+	})
+
+	t.Run("should apply the request validator rules to mutations", func(t *testing.T) {
+		h, repo := setupGraphQL(t)
+
+		resp := doGraphQL(t, h, `mutation { createCourse(input: {title: "", description: "d"}) { id } }`, nil)
+
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "invalid_input", resp.Errors[0].Extensions["code"])
+		assert.NotEmpty(t, resp.Errors[0].Extensions["details"])
+		repo.AssertNotCalled(t, "CreateCourse", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject queries over the complexity limit", func(t *testing.T) {
+		h, repo := setupGraphQL(t)
+		ids := make([]any, 10)
+		for i := range ids {
+			ids[i] = idA
+		}
+
+		resp := doGraphQL(t, h, `query($ids: [ID!]!) { courses(ids: $ids) { id title } }`, map[string]any{"ids": ids})
+
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "query exceeds the maximum allowed complexity", resp.Errors[0].Message)
+		repo.AssertNotCalled(t, "GetCoursesByIDs", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type ListAPIKeysHandler struct {
+	apiKeyService port.APIKeyServicePort
+}
+
+func NewListAPIKeysHandler(apiKeyService port.APIKeyServicePort) *ListAPIKeysHandler {
+	return &ListAPIKeysHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// Handle godoc
+// @Summary      List API keys
+// @Description  Lists every API key, including revoked and expired ones. Secrets are never returned.
+// @Tags         API Keys
+// @Produce      json
+// @Success      200  {array}   APIKeyResponse
+// @Failure      403  {object}  ErrorResponse "Missing apikey:manage permission"
+// @Security     BearerAuth
+// @Router       /api-keys [get]
+func (h *ListAPIKeysHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := web.GetLogger(ctx)
+
+	keys, err := h.apiKeyService.ListAPIKeys(ctx)
+	if err != nil {
+		logger.Error("failed to list api keys", "error", err)
+		web.Error(w, r, err)
+		return
+	}
+
+	response := make([]APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, newAPIKeyResponse(key))
+	}
+
+	web.Success(w, r, http.StatusOK, response)
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type AuditEntryResponse struct {
+	ID         string                `json:"id"`
+	CourseID   string                `json:"course_id"`
+	Action     string                `json:"action"`
+	Actor      string                `json:"actor"`
+	RequestID  string                `json:"request_id,omitempty"`
+	Before     *model.CourseSnapshot `json:"before"`
+	After      *model.CourseSnapshot `json:"after"`
+	Changes    model.FieldChanges    `json:"changes"`
+	CoActors   []string              `json:"co_actors,omitempty"`
+	OccurredAt string                `json:"occurred_at"`
+}
+
+type ListCourseAuditResponse struct {
+	Entries    []AuditEntryResponse `json:"entries"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+type ListCourseAuditHandler struct {
+	auditService port.AuditServicePort
+}
+
+func NewListCourseAuditHandler(auditService port.AuditServicePort) *ListCourseAuditHandler {
+	return &ListCourseAuditHandler{
+		auditService: auditService,
+	}
+}
+
+// Handle godoc
+// @Summary      List course audit entries
+// @Description  Lists who changed which course and how, newest first. Pass next_cursor as cursor to get the next page.
+// @Tags         Audit
+// @Produce      json
+// @Param        course_id  query     string  false  "Course id"
+// @Param        actor      query     string  false  "Actor (JWT subject, apikey:<id> or system:<id>)"
+// @Param        from       query     string  false  "Entries at or after this RFC 3339 time"
+// @Param        to         query     string  false  "Entries before this RFC 3339 time"
+// @Param        limit      query     int     false  "Page size, 50 by default and at most 500"
+// @Param        cursor     query     string  false  "Cursor returned by the previous page"
+// @Success      200        {object}  ListCourseAuditResponse
+// @Failure      400        {object}  ErrorResponse "Invalid filter"
+// @Failure      403        {object}  ErrorResponse "Missing audit:read permission"
+// @Security     BearerAuth
+// @Router       /audit/courses [get]
+func (h *ListCourseAuditHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := web.GetLogger(ctx)
+
+	filter, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		logger.Warn("invalid audit filter", "error", err)
+		web.Error(w, r, err)
+		return
+	}
+
+	entries, next, err := h.auditService.ListAuditEntries(ctx, filter)
+	if err != nil {
+		logger.Error("failed to list audit entries", "error", err)
+		web.Error(w, r, err)
+		return
+	}
+
+	response := ListCourseAuditResponse{
+		Entries:    make([]AuditEntryResponse, 0, len(entries)),
+		NextCursor: next,
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, newAuditEntryResponse(entry))
+	}
+
+	web.Success(w, r, http.StatusOK, response)
+}
+
+func newAuditEntryResponse(entry *model.AuditEntry) AuditEntryResponse {
+	return AuditEntryResponse{
+		ID:         entry.ID,
+		CourseID:   entry.CourseID,
+		Action:     string(entry.Action),
+		Actor:      entry.Actor,
+		RequestID:  entry.RequestID,
+		Before:     entry.Before,
+		After:      entry.After,
+		Changes:    entry.Changes,
+		CoActors:   entry.CoActors,
+		OccurredAt: entry.OccurredAt.Format(time.RFC3339Nano),
+	}
+}
+
+func parseAuditFilter(query url.Values) (model.AuditFilter, error) {
+	invalid := func(param, message string) error {
+		return fault.New(message, fault.WithCode(fault.Invalid), fault.WithContext("param", param))
+	}
+
+	filter := model.AuditFilter{
+		CourseID: query.Get("course_id"),
+		Actor:    query.Get("actor"),
+		Cursor:   query.Get("cursor"),
+	}
+
+	if filter.CourseID != "" {
+		if _, err := uuid.Parse(filter.CourseID); err != nil {
+			return filter, invalid("course_id", "invalid course_id, must be a valid uuid")
+		}
+	}
+	if filter.Cursor != "" {
+		if _, err := uuid.Parse(filter.Cursor); err != nil {
+			return filter, invalid("cursor", "invalid cursor")
+		}
+	}
+
+	for param, dest := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		raw := query.Get(param)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, invalid(param, "invalid "+param+", must be an RFC 3339 time")
+		}
+		*dest = &t
+	}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return filter, invalid("limit", "invalid limit, must be a positive integer")
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type CourseRevisionResponse struct {
+	Revision    int    `json:"revision"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Actor       string `json:"actor"`
+	RollbackOf  *int   `json:"rollback_of,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type ListCourseRevisionsResponse struct {
+	Revisions []CourseRevisionResponse `json:"revisions"`
+}
+
+type ListCourseRevisionsHandler struct {
+	courseService port.CourseServicePort
+}
+
+func NewListCourseRevisionsHandler(courseService port.CourseServicePort) *ListCourseRevisionsHandler {
+	return &ListCourseRevisionsHandler{
+		courseService: courseService,
+	}
+}
+
+// Handle godoc
+// @Summary      List course revisions
+// @Description  Lists every version of a course, newest first.
+// @Tags         Courses
+// @Produce      json
+// @Param        id   path      string  true  "Course id"
+// @Success      200  {object}  ListCourseRevisionsResponse
+// @Failure      400  {object}  ErrorResponse "Invalid id"
+// @Failure      404  {object}  ErrorResponse "Course not found"
+// @Security     BearerAuth
+// @Router       /courses/{id}/revisions [get]
+func (h *ListCourseRevisionsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := web.GetLogger(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(idStr); err != nil {
+		logger.Warn("invalid uuid format in url param", "id", idStr, "error", err)
+		web.Error(w, r, fault.New("invalid id format, must be a valid uuid", fault.WithCode(fault.Invalid)))
+		return
+	}
+
+	revisions, err := h.courseService.ListCourseRevisions(ctx, idStr)
+	if err != nil {
+		logger.Warn("failed to list course revisions", "id", idStr, "error", err)
+		web.Error(w, r, revisionError(err))
+		return
+	}
+
+	response := ListCourseRevisionsResponse{
+		Revisions: make([]CourseRevisionResponse, 0, len(revisions)),
+	}
+	for _, revision := range revisions {
+		response.Revisions = append(response.Revisions, newCourseRevisionResponse(revision))
+	}
+
+	web.Success(w, r, http.StatusOK, response)
+}
+
+func newCourseRevisionResponse(revision *model.CourseRevision) CourseRevisionResponse {
+	return CourseRevisionResponse{
+		Revision:    revision.Revision,
+		Title:       revision.Title,
+		Description: revision.Description,
+		Actor:       revision.Actor,
+		RollbackOf:  revision.RollbackOf,
+		CreatedAt:   revision.CreatedAt.Format(time.RFC3339Nano),
+	}
+}
+
+// parseRevision reads a revision number from a url or query parameter.
+func parseRevision(param, raw string) (int, error) {
+	revision, err := strconv.Atoi(raw)
+	if err != nil || revision <= 0 {
+		return 0, fault.New("invalid revision, must be a positive integer",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("param", param),
+		)
+	}
+	return revision, nil
+}
+
+func revisionError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrCourseNotFound):
+		return fault.New("course not found", fault.WithCode(fault.NotFound))
+	case errors.Is(err, model.ErrRevisionNotFound):
+		return fault.New("course revision not found", fault.WithCode(fault.NotFound))
+	}
+	return err
+}
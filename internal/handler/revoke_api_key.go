@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type RevokeAPIKeyHandler struct {
+	apiKeyService port.APIKeyServicePort
+}
+
+func NewRevokeAPIKeyHandler(apiKeyService port.APIKeyServicePort) *RevokeAPIKeyHandler {
+	return &RevokeAPIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// Handle godoc
+// @Summary      Revoke an API key
+// @Tags         API Keys
+// @Param        id   path      string  true  "API key ID"
+// @Success      204
+// @Failure      404  {object}  ErrorResponse "API key not found or already revoked"
+// @Security     BearerAuth
+// @Router       /api-keys/{id} [delete]
+func (h *RevokeAPIKeyHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := web.GetLogger(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(idStr); err != nil {
+		logger.Warn("invalid uuid format in url param", "id", idStr, "error", err)
+		web.Error(w, r, fault.New("invalid id format, must be a valid uuid", fault.WithCode(fault.Invalid)))
+		return
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(ctx, idStr); err != nil {
+		if errors.Is(err, model.ErrAPIKeyNotFound) {
+			logger.Warn("api key not found for revocation", "id", idStr)
+			web.Error(w, r, fault.New("api key not found", fault.WithCode(fault.NotFound)))
+			return
+		}
+
+		logger.Error("failed to revoke api key", "id", idStr, "error", err)
+		web.Error(w, r, err)
+		return
+	}
+
+	logger.Info("api key revoked successfully", "api_key_id", idStr)
+	web.Success(w, r, http.StatusNoContent, nil)
+}
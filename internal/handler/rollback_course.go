@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type RollbackCourseHandler struct {
+	courseService port.CourseServicePort
+}
+
+func NewRollbackCourseHandler(courseService port.CourseServicePort) *RollbackCourseHandler {
+	return &RollbackCourseHandler{
+		courseService: courseService,
+	}
+}
+
+// Handle godoc
+// @Summary      Roll a course back to a revision
+// @Description  Restores the content of an older revision as a new revision. History is never rewritten.
+// @Tags         Courses
+// @Produce      json
+// @Param        id        path      string  true  "Course id"
+// @Param        revision  path      int     true  "Revision to restore"
+// @Success      201       {object}  CourseRevisionResponse
+// @Failure      400       {object}  ErrorResponse "Invalid id or revision"
+// @Failure      404       {object}  ErrorResponse "Revision not found"
+// @Failure      422       {object}  ErrorResponse "Revision cannot be restored"
+// @Security     BearerAuth
+// @Router       /courses/{id}/revisions/{revision}/rollback [post]
+func (h *RollbackCourseHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := web.GetLogger(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(idStr); err != nil {
+		logger.Warn("invalid uuid format in url param", "id", idStr, "error", err)
+		web.Error(w, r, fault.New("invalid id format, must be a valid uuid", fault.WithCode(fault.Invalid)))
+		return
+	}
+
+	number, err := parseRevision("revision", chi.URLParam(r, "revision"))
+	if err != nil {
+		web.Error(w, r, err)
+		return
+	}
+
+	revision, err := h.courseService.RollbackCourse(ctx, idStr, number)
+	if err != nil {
+		logger.Warn("failed to roll back course", "id", idStr, "revision", number, "error", err)
+		web.Error(w, r, revisionError(err))
+		return
+	}
+
+	logger.Info("course rolled back", "course_id", idStr, "revision", revision.Revision, "rollback_of", number)
+
+	web.Success(w, r, http.StatusCreated, newCourseRevisionResponse(revision))
+}
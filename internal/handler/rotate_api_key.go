@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type RotateAPIKeyHandler struct {
+	apiKeyService port.APIKeyServicePort
+}
+
+func NewRotateAPIKeyHandler(apiKeyService port.APIKeyServicePort) *RotateAPIKeyHandler {
+	return &RotateAPIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// Handle godoc
+// @Summary      Rotate an API key
+// @Description  Issues a new key with the same name, scopes and expiry and revokes the old one.
+// @Tags         API Keys
+// @Produce      json
+// @Param        id   path      string  true  "API key ID"
+// @Success      201  {object}  CreateAPIKeyResponse
+// @Failure      404  {object}  ErrorResponse "API key not found"
+// @Failure      422  {object}  ErrorResponse "API key revoked or expired"
+// @Security     BearerAuth
+// @Router       /api-keys/{id}/rotate [post]
+func (h *RotateAPIKeyHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := web.GetLogger(ctx)
+
+	idStr := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(idStr); err != nil {
+		logger.Warn("invalid uuid format in url param", "id", idStr, "error", err)
+		web.Error(w, r, fault.New("invalid id format, must be a valid uuid", fault.WithCode(fault.Invalid)))
+		return
+	}
+
+	key, token, err := h.apiKeyService.RotateAPIKey(ctx, idStr)
+	if err != nil {
+		if errors.Is(err, model.ErrAPIKeyNotFound) {
+			logger.Warn("api key not found for rotation", "id", idStr)
+			web.Error(w, r, fault.New("api key not found", fault.WithCode(fault.NotFound)))
+			return
+		}
+
+		logger.Error("failed to rotate api key", "id", idStr, "error", err)
+		web.Error(w, r, err)
+		return
+	}
+
+	logger.Info("api key rotated successfully", "api_key_id", key.ID, "rotated_from", idStr)
+	web.Success(w, r, http.StatusCreated, CreateAPIKeyResponse{
+		APIKeyResponse: newAPIKeyResponse(key),
+		Key:            token,
+	})
+}
@@ -4,6 +4,12 @@ import (
 	"github.com/go-chi/chi/v5"
 	httpSwagger "github.com/swaggo/http-swagger"
 
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/pkg/auth"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/health"
+	"github.com/marcelofabianov/dojo-go/pkg/idempotency"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
 	"github.com/marcelofabianov/dojo-go/pkg/web"
 )
 
@@ -13,21 +19,80 @@ func RegisterRoutes(
 	getCourseHandler *GetCourseHandler,
 	deleteCourseHandler *DeleteCourseHandler,
 	updateCourseHandler *UpdateCourseHandler,
+	listCourseRevisionsHandler *ListCourseRevisionsHandler,
+	getCourseRevisionHandler *GetCourseRevisionHandler,
+	diffCourseRevisionsHandler *DiffCourseRevisionsHandler,
+	rollbackCourseHandler *RollbackCourseHandler,
+	courseEventsHandler *CourseEventsHandler,
+	courseCollabHandler *CourseCollabHandler,
+	graphQLHandler *GraphQLHandler,
+	batchCoursesHandler *BatchCoursesHandler,
+	createAPIKeyHandler *CreateAPIKeyHandler,
+	listAPIKeysHandler *ListAPIKeysHandler,
+	revokeAPIKeyHandler *RevokeAPIKeyHandler,
+	rotateAPIKeyHandler *RotateAPIKeyHandler,
+	listCourseAuditHandler *ListCourseAuditHandler,
+	exportCourseAuditHandler *ExportCourseAuditHandler,
+	apiKeyMiddleware *APIKeyMiddleware,
+	idempotencyMiddleware *idempotency.Middleware,
+	authMiddleware *auth.Middleware,
+	tenantMiddleware *tenant.Middleware,
+	policy *authz.Policy,
+	healthRegistry *health.Registry,
+	replicas *db.ReplicaSet,
 ) {
 	// General
 	r.Get("/", web.IndexHandler)
-	r.Get("/healthz", web.HealthCheckHandler)
+
+	// Probes
+	r.Get("/livez", healthRegistry.LiveHandler)
+	r.Get("/readyz", healthRegistry.ReadyHandler)
+	r.Get("/ping", healthRegistry.LiveHandler)
+	r.Get("/healthz", healthRegistry.ReadyHandler)
 
 	// Swagger
 	r.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
 	))
 
-	// Courses
-	r.Route("/api/v1/courses", func(r chi.Router) {
-		r.Post("/", createCourseHandler.Handle)
-		r.Get("/{id}", getCourseHandler.Handle)
-		r.Delete("/{id}", deleteCourseHandler.Handle)
-		r.Put("/{id}", updateCourseHandler.Handle)
+	r.Group(func(r chi.Router) {
+		r.Use(replicas.Middleware)
+		r.Use(apiKeyMiddleware.Handler)
+		r.Use(authMiddleware.Handler)
+		r.Use(authz.PrincipalMiddleware)
+		r.Use(tenantMiddleware.Handler)
+		r.Use(tenantMiddleware.RateLimit)
+
+		// Courses
+		r.Route("/api/v1/courses", func(r chi.Router) {
+			r.With(idempotencyMiddleware.Handler).Post("/", createCourseHandler.Handle)
+			r.With(authz.RequirePermission(policy, authz.CourseRead)).Get("/events", courseEventsHandler.Handle)
+			r.Get("/{id}", getCourseHandler.Handle)
+			r.Delete("/{id}", deleteCourseHandler.Handle)
+			r.Put("/{id}", updateCourseHandler.Handle)
+			r.With(authz.RequirePermission(policy, authz.CourseUpdate)).Get("/{id}/collab", courseCollabHandler.Handle)
+			r.Get("/{id}/revisions", listCourseRevisionsHandler.Handle)
+			r.Get("/{id}/revisions/diff", diffCourseRevisionsHandler.Handle)
+			r.Get("/{id}/revisions/{revision}", getCourseRevisionHandler.Handle)
+			r.With(idempotencyMiddleware.Handler).Post("/{id}/revisions/{revision}/rollback", rollbackCourseHandler.Handle)
+		})
+		r.With(idempotencyMiddleware.Handler).Post("/api/v1/courses:batch", batchCoursesHandler.Handle)
+
+		// GraphQL
+		r.Post("/api/v1/graphql", graphQLHandler.Handle)
+
+		// API Keys
+		r.Route("/api/v1/api-keys", func(r chi.Router) {
+			r.Post("/", createAPIKeyHandler.Handle)
+			r.Get("/", listAPIKeysHandler.Handle)
+			r.Delete("/{id}", revokeAPIKeyHandler.Handle)
+			r.Post("/{id}/rotate", rotateAPIKeyHandler.Handle)
+		})
+
+		// Audit
+		r.Route("/api/v1/audit/courses", func(r chi.Router) {
+			r.Get("/", listCourseAuditHandler.Handle)
+			r.Get("/export", exportCourseAuditHandler.Handle)
+		})
 	})
 }
@@ -0,0 +1,70 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (_m *MockAPIKeyRepository) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	ret := _m.Called(ctx, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.APIKey) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+func (_m *MockAPIKeyRepository) GetAPIKeyByID(ctx context.Context, id string) (*model.APIKey, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.APIKey
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.APIKey)
+	}
+
+	return r0, ret.Error(1)
+}
+
+func (_m *MockAPIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	ret := _m.Called(ctx, prefix)
+
+	var r0 *model.APIKey
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*model.APIKey)
+	}
+
+	return r0, ret.Error(1)
+}
+
+func (_m *MockAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*model.APIKey
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]*model.APIKey)
+	}
+
+	return r0, ret.Error(1)
+}
+
+func (_m *MockAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	ret := _m.Called(ctx, id, revokedAt)
+	return ret.Error(0)
+}
+
+func (_m *MockAPIKeyRepository) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	ret := _m.Called(ctx, id, usedAt)
+	return ret.Error(0)
+}
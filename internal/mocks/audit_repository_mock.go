@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (_m *MockAuditRepository) AppendAuditEntry(ctx context.Context, entry *model.AuditEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.AuditEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+func (_m *MockAuditRepository) ListAuditEntries(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*model.AuditEntry
+	if rf, ok := ret.Get(0).(func(context.Context, model.AuditFilter) []*model.AuditEntry); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.AuditEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, model.AuditFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAuditRepositoryStub accepts every audit entry, for tests that do not
+// assert on the audit log.
+func NewAuditRepositoryStub() *MockAuditRepository {
+	m := new(MockAuditRepository)
+	m.On("AppendAuditEntry", mock.Anything, mock.Anything).Return(nil)
+	return m
+}
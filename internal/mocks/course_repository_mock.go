@@ -47,6 +47,50 @@ func (_m *MockCourseRepository) GetCourseByID(ctx context.Context, id string) (*
 	return r0, r1
 }
 
+func (_m *MockCourseRepository) GetCoursesByIDs(ctx context.Context, ids []string) ([]*model.Course, error) {
+	ret := _m.Called(ctx, ids)
+
+	var r0 []*model.Course
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*model.Course); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Course)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *MockCourseRepository) ListCourses(ctx context.Context, limit int) ([]*model.Course, error) {
+	ret := _m.Called(ctx, limit)
+
+	var r0 []*model.Course
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*model.Course); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Course)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 func (_m *MockCourseRepository) UpdateCourse(ctx context.Context, course *model.Course) error {
 	ret := _m.Called(ctx, course)
 
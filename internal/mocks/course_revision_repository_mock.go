@@ -0,0 +1,78 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+)
+
+type MockCourseRevisionRepository struct {
+	mock.Mock
+}
+
+func (_m *MockCourseRevisionRepository) CreateCourseRevision(ctx context.Context, revision *model.CourseRevision) error {
+	ret := _m.Called(ctx, revision)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.CourseRevision) error); ok {
+		r0 = rf(ctx, revision)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+func (_m *MockCourseRevisionRepository) ListCourseRevisions(ctx context.Context, courseID string) ([]*model.CourseRevision, error) {
+	ret := _m.Called(ctx, courseID)
+
+	var r0 []*model.CourseRevision
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.CourseRevision); ok {
+		r0 = rf(ctx, courseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.CourseRevision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, courseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *MockCourseRevisionRepository) GetCourseRevision(ctx context.Context, courseID string, revision int) (*model.CourseRevision, error) {
+	ret := _m.Called(ctx, courseID, revision)
+
+	var r0 *model.CourseRevision
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *model.CourseRevision); ok {
+		r0 = rf(ctx, courseID, revision)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.CourseRevision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, courseID, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCourseRevisionRepositoryStub accepts every new revision, for tests that
+// do not assert on revision history.
+func NewCourseRevisionRepositoryStub() *MockCourseRevisionRepository {
+	m := new(MockCourseRevisionRepository)
+	m.On("CreateCourseRevision", mock.Anything, mock.Anything).Return(nil)
+	return m
+}
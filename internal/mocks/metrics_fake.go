@@ -0,0 +1,15 @@
+package mocks
+
+import "sync"
+
+// FakeMetrics records the domain events counted by services.
+type FakeMetrics struct {
+	mu     sync.Mutex
+	Events []string
+}
+
+func (m *FakeMetrics) CountEvent(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Events = append(m.Events, name)
+}
@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"context"
+	"database/sql"
+)
+
+type fakeTxCtxKey struct{}
+
+// FakeTransactor runs functions inline and records whether the outermost
+// transaction was committed or rolled back. Nested calls run inline, with no
+// savepoint. AfterCommit functions run when the outermost call succeeds.
+// Options holds the options of the outermost call.
+type FakeTransactor struct {
+	Committed  bool
+	RolledBack bool
+	Options    *sql.TxOptions
+	hooks      []func()
+}
+
+func (t *FakeTransactor) WithinTx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	if ctx.Value(fakeTxCtxKey{}) != nil {
+		return fn(ctx)
+	}
+
+	t.Options = opts
+	t.hooks = nil
+	if err := fn(context.WithValue(ctx, fakeTxCtxKey{}, t)); err != nil {
+		t.RolledBack = true
+		return err
+	}
+	t.Committed = true
+	for _, hook := range t.hooks {
+		hook()
+	}
+	return nil
+}
+
+// AfterCommit defers fn to the outermost fake transaction bound to ctx,
+// which may belong to another FakeTransactor.
+func (t *FakeTransactor) AfterCommit(ctx context.Context, fn func()) {
+	outer, ok := ctx.Value(fakeTxCtxKey{}).(*FakeTransactor)
+	if !ok {
+		fn()
+		return
+	}
+	outer.hooks = append(outer.hooks, fn)
+}
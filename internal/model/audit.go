@@ -0,0 +1,176 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditCourseCreated    AuditAction = "course.created"
+	AuditCourseUpdated    AuditAction = "course.updated"
+	AuditCourseDeleted    AuditAction = "course.deleted"
+	AuditCourseRolledBack AuditAction = "course.rolled_back"
+)
+
+// AuditEntry records who changed a course, how and when. Before is empty for
+// creations and After for deletions.
+type AuditEntry struct {
+	ID         string          `db:"id"`
+	TenantID   string          `db:"tenant_id"`
+	CourseID   string          `db:"course_id"`
+	Action     AuditAction     `db:"action"`
+	Actor      string          `db:"actor"`
+	RequestID  string          `db:"request_id"`
+	Before     *CourseSnapshot `db:"before"`
+	After      *CourseSnapshot `db:"after"`
+	Changes    FieldChanges    `db:"changes"`
+	CoActors   Actors          `db:"co_actors"`
+	OccurredAt time.Time       `db:"occurred_at"`
+}
+
+type NewAuditEntryInput struct {
+	Action    AuditAction
+	Actor     string
+	CoActors  []string
+	RequestID string
+	Before    *Course
+	After     *Course
+}
+
+// AuditFilter selects audit entries. Entries are returned newest first and
+// Cursor, the id of the last entry of the previous page, continues a listing.
+type AuditFilter struct {
+	CourseID string
+	Actor    string
+	From     *time.Time
+	To       *time.Time
+	Cursor   string
+	Limit    int
+}
+
+// CourseSnapshot is the state of a course stored in the audit log.
+type CourseSnapshot struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type FieldChange struct {
+	From *string `json:"from"`
+	To   *string `json:"to"`
+}
+
+// Actors lists who took part in a change besides the Actor of its audit
+// entry, e.g. the other editors of a collaborative session.
+type Actors []string
+
+// FieldChanges maps each changed course field to its old and new values.
+type FieldChanges map[string]FieldChange
+
+func NewAuditEntry(input NewAuditEntryInput) (*AuditEntry, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &AuditEntry{
+		ID:         id.String(),
+		Action:     input.Action,
+		Actor:      input.Actor,
+		CoActors:   append(Actors{}, input.CoActors...),
+		RequestID:  input.RequestID,
+		Before:     snapshotOf(input.Before),
+		After:      snapshotOf(input.After),
+		OccurredAt: time.Now(),
+	}
+	entry.Changes = diffSnapshots(entry.Before, entry.After)
+
+	if input.After != nil {
+		entry.CourseID = input.After.ID
+	} else if input.Before != nil {
+		entry.CourseID = input.Before.ID
+	}
+
+	return entry, nil
+}
+
+func snapshotOf(c *Course) *CourseSnapshot {
+	if c == nil {
+		return nil
+	}
+	return &CourseSnapshot{
+		ID:          c.ID,
+		Title:       c.Title,
+		Description: c.Description,
+		CreatedAt:   c.CreatedAt,
+	}
+}
+
+func diffSnapshots(before, after *CourseSnapshot) FieldChanges {
+	fields := func(s *CourseSnapshot) map[string]*string {
+		if s == nil {
+			return map[string]*string{"title": nil, "description": nil}
+		}
+		return map[string]*string{"title": &s.Title, "description": &s.Description}
+	}
+
+	old, updated := fields(before), fields(after)
+	changes := FieldChanges{}
+	for field, from := range old {
+		to := updated[field]
+		if from == nil && to == nil || from != nil && to != nil && *from == *to {
+			continue
+		}
+		changes[field] = FieldChange{From: from, To: to}
+	}
+
+	return changes
+}
+
+func (s CourseSnapshot) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+func (s *CourseSnapshot) Scan(src any) error {
+	return scanJSON(src, s)
+}
+
+func (c FieldChanges) Value() (driver.Value, error) {
+	if c == nil {
+		c = FieldChanges{}
+	}
+	return json.Marshal(c)
+}
+
+func (c *FieldChanges) Scan(src any) error {
+	return scanJSON(src, c)
+}
+
+func (a Actors) Value() (driver.Value, error) {
+	if a == nil {
+		a = Actors{}
+	}
+	return json.Marshal(a)
+}
+
+func (a *Actors) Scan(src any) error {
+	return scanJSON(src, a)
+}
+
+func scanJSON(src any, dest any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, dest)
+	}
+}
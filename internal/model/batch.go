@@ -0,0 +1,32 @@
+package model
+
+type BatchMode string
+
+const (
+	// BatchAtomic applies every operation or none of them.
+	BatchAtomic BatchMode = "atomic"
+	// BatchBestEffort applies each operation independently.
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+type BatchOperationType string
+
+const (
+	BatchCreate BatchOperationType = "create"
+	BatchUpdate BatchOperationType = "update"
+	BatchDelete BatchOperationType = "delete"
+)
+
+type BatchOperation struct {
+	Type        BatchOperationType
+	ID          string
+	Title       string
+	Description string
+}
+
+// BatchResult is the outcome of the operation at the same index. Course is
+// nil for deletes and failed operations.
+type BatchResult struct {
+	Course *Course
+	Err    error
+}
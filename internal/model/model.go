@@ -32,6 +32,7 @@ type UpdateCourseInput struct {
 
 type Course struct {
 	ID          string    `db:"id"`
+	TenantID    string    `db:"tenant_id"`
 	Title       string    `db:"title"`
 	Description string    `db:"description"`
 	CreatedAt   time.Time `db:"created_at"`
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrRevisionNotFound = errors.New("course revision not found")
+
+// CourseRevision is one version of a course. Revisions are numbered from 1
+// per course; RollbackOf is set on revisions created by restoring an older
+// one.
+type CourseRevision struct {
+	CourseID    string    `db:"course_id"`
+	TenantID    string    `db:"tenant_id"`
+	Revision    int       `db:"revision"`
+	Title       string    `db:"title"`
+	Description string    `db:"description"`
+	Actor       string    `db:"actor"`
+	RollbackOf  *int      `db:"rollback_of"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func NewCourseRevision(course *Course, actor string, rollbackOf *int) *CourseRevision {
+	return &CourseRevision{
+		CourseID:    course.ID,
+		Title:       course.Title,
+		Description: course.Description,
+		Actor:       actor,
+		RollbackOf:  rollbackOf,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// DiffRevisions returns the fields that changed from one revision to another.
+func DiffRevisions(from, to *CourseRevision) FieldChanges {
+	return diffSnapshots(from.snapshot(), to.snapshot())
+}
+
+func (r *CourseRevision) snapshot() *CourseSnapshot {
+	return &CourseSnapshot{ID: r.CourseID, Title: r.Title, Description: r.Description}
+}
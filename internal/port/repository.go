@@ -2,6 +2,8 @@ package port
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/marcelofabianov/dojo-go/internal/model"
 )
@@ -9,6 +11,41 @@ import (
 type CourseRepositoryPort interface {
 	CreateCourse(ctx context.Context, course *model.Course) error
 	GetCourseByID(ctx context.Context, id string) (*model.Course, error)
+	// GetCoursesByIDs returns the courses among ids in one round trip, in no
+	// particular order. Unknown ids and courses of other tenants are skipped.
+	GetCoursesByIDs(ctx context.Context, ids []string) ([]*model.Course, error)
+	ListCourses(ctx context.Context, limit int) ([]*model.Course, error)
 	DeleteCourseByID(ctx context.Context, id string) error
 	UpdateCourse(ctx context.Context, course *model.Course) error
 }
+
+type APIKeyRepositoryPort interface {
+	CreateAPIKey(ctx context.Context, key *model.APIKey) error
+	GetAPIKeyByID(ctx context.Context, id string) (*model.APIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*model.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]*model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error
+	TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error
+}
+
+type CourseRevisionRepositoryPort interface {
+	CreateCourseRevision(ctx context.Context, revision *model.CourseRevision) error
+	ListCourseRevisions(ctx context.Context, courseID string) ([]*model.CourseRevision, error)
+	GetCourseRevision(ctx context.Context, courseID string, revision int) (*model.CourseRevision, error)
+}
+
+type AuditRepositoryPort interface {
+	AppendAuditEntry(ctx context.Context, entry *model.AuditEntry) error
+	ListAuditEntries(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error)
+}
+
+// TransactorPort runs fn as one unit of work: the repositories called with
+// the ctx it receives share a transaction that is committed when fn returns
+// nil and rolled back otherwise. Nested calls roll back on their own and
+// keep the options of the outermost call; nil opts uses the driver defaults.
+// AfterCommit defers fn until the outermost transaction bound to ctx commits
+// and drops it if the work it followed is rolled back.
+type TransactorPort interface {
+	WithinTx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error
+	AfterCommit(ctx context.Context, fn func())
+}
@@ -9,6 +9,34 @@ import (
 type CourseServicePort interface {
 	CreateCourse(ctx context.Context, input model.NewCourseInput) (*model.Course, error)
 	GetCourseByID(ctx context.Context, id string) (*model.Course, error)
+	GetCoursesByIDs(ctx context.Context, ids []string) ([]*model.Course, error)
+	ListCourses(ctx context.Context, limit int) ([]*model.Course, error)
 	DeleteCourseByID(ctx context.Context, id string) error
 	UpdateCourse(ctx context.Context, id string, input model.UpdateCourseInput) (*model.Course, error)
+	ListCourseRevisions(ctx context.Context, id string) ([]*model.CourseRevision, error)
+	GetCourseRevision(ctx context.Context, id string, revision int) (*model.CourseRevision, error)
+	DiffCourseRevisions(ctx context.Context, id string, from, to int) (model.FieldChanges, error)
+	RollbackCourse(ctx context.Context, id string, revision int) (*model.CourseRevision, error)
+}
+
+type APIKeyServicePort interface {
+	CreateAPIKey(ctx context.Context, input model.NewAPIKeyInput) (*model.APIKey, string, error)
+	ListAPIKeys(ctx context.Context) ([]*model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	RotateAPIKey(ctx context.Context, id string) (*model.APIKey, string, error)
+	AuthenticateAPIKey(ctx context.Context, token string) (*model.APIKey, error)
+}
+
+type CourseBatchServicePort interface {
+	ExecuteBatch(ctx context.Context, mode model.BatchMode, ops []model.BatchOperation) []model.BatchResult
+}
+
+type AuditServicePort interface {
+	ListAuditEntries(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, string, error)
+	ExportAuditEntries(ctx context.Context, filter model.AuditFilter, fn func(*model.AuditEntry) error) error
+}
+
+// MetricsPort counts committed domain events, such as course.created.
+type MetricsPort interface {
+	CountEvent(name string)
 }
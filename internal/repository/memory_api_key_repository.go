@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+type MemoryAPIKeyRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryAPIKeyRepository(store *MemoryStore) port.APIKeyRepositoryPort {
+	return &MemoryAPIKeyRepository{store: store}
+}
+
+func (r *MemoryAPIKeyRepository) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	key.TenantID = tenantID
+
+	return r.store.write(ctx, func() (func(), error) {
+		for _, existing := range r.store.apiKeys {
+			if existing.ID == key.ID || existing.Prefix == key.Prefix {
+				return nil, fault.New("failed to insert api key, id or prefix already exists",
+					fault.WithCode(fault.Internal),
+					fault.WithContext("id", key.ID),
+				)
+			}
+		}
+
+		stored := *key
+		stored.Scopes = slices.Clone(key.Scopes)
+		r.store.apiKeys[key.ID] = stored
+
+		return func() { delete(r.store.apiKeys, key.ID) }, nil
+	})
+}
+
+func (r *MemoryAPIKeyRepository) GetAPIKeyByID(ctx context.Context, id string) (*model.APIKey, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	key, ok := r.store.apiKeys[id]
+	if !ok || key.TenantID != tenantID {
+		return nil, model.ErrAPIKeyNotFound
+	}
+
+	return cloneAPIKey(key), nil
+}
+
+// GetAPIKeyByPrefix is not tenant scoped: it runs during authentication,
+// before the tenant is known, and the key it returns decides the tenant.
+func (r *MemoryAPIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, key := range r.store.apiKeys {
+		if key.Prefix == prefix {
+			return cloneAPIKey(key), nil
+		}
+	}
+
+	return nil, model.ErrAPIKeyNotFound
+}
+
+func (r *MemoryAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	keys := []*model.APIKey{}
+	for _, key := range r.store.apiKeys {
+		if key.TenantID == tenantID {
+			keys = append(keys, cloneAPIKey(key))
+		}
+	}
+	r.store.mu.RUnlock()
+
+	slices.SortFunc(keys, func(a, b *model.APIKey) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return keys, nil
+}
+
+func (r *MemoryAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.store.write(ctx, func() (func(), error) {
+		before, ok := r.store.apiKeys[id]
+		if !ok || before.TenantID != tenantID || before.RevokedAt != nil {
+			return nil, model.ErrAPIKeyNotFound
+		}
+
+		revoked := before
+		revoked.RevokedAt = &revokedAt
+		r.store.apiKeys[id] = revoked
+
+		return func() { r.store.apiKeys[id] = before }, nil
+	})
+}
+
+func (r *MemoryAPIKeyRepository) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.store.write(ctx, func() (func(), error) {
+		before, ok := r.store.apiKeys[id]
+		if !ok || before.TenantID != tenantID {
+			return nil, nil
+		}
+
+		touched := before
+		touched.LastUsedAt = &usedAt
+		r.store.apiKeys[id] = touched
+
+		return func() { r.store.apiKeys[id] = before }, nil
+	})
+}
+
+func cloneAPIKey(key model.APIKey) *model.APIKey {
+	key.Scopes = slices.Clone(key.Scopes)
+	return &key
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+// MemoryAuditRepository only ever appends, like the audit table in Postgres.
+type MemoryAuditRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryAuditRepository(store *MemoryStore) port.AuditRepositoryPort {
+	return &MemoryAuditRepository{store: store}
+}
+
+func (r *MemoryAuditRepository) AppendAuditEntry(ctx context.Context, entry *model.AuditEntry) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	entry.TenantID = tenantID
+
+	return r.store.write(ctx, func() (func(), error) {
+		n := len(r.store.audit)
+		r.store.audit = append(r.store.audit, *entry)
+		return func() { r.store.audit = r.store.audit[:n] }, nil
+	})
+}
+
+// ListAuditEntries applies the same filter and ordering as the Postgres
+// repository: newest first, by id, which is a UUIDv7.
+func (r *MemoryAuditRepository) ListAuditEntries(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	entries := []*model.AuditEntry{}
+	for _, entry := range r.store.audit {
+		switch {
+		case entry.TenantID != tenantID:
+		case filter.CourseID != "" && entry.CourseID != filter.CourseID:
+		case filter.Actor != "" && entry.Actor != filter.Actor:
+		case filter.From != nil && entry.OccurredAt.Before(*filter.From):
+		case filter.To != nil && !entry.OccurredAt.Before(*filter.To):
+		case filter.Cursor != "" && entry.ID >= filter.Cursor:
+		default:
+			entries = append(entries, &entry)
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b *model.AuditEntry) int {
+		return strings.Compare(b.ID, a.ID)
+	})
+
+	return entries[:min(max(filter.Limit, 0), len(entries))], nil
+}
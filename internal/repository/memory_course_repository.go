@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+// MemoryCourseRepository behaves like PostgresCourseRepository, tenant
+// scoping and errors included, with the courses kept in a MemoryStore.
+// Courses are copied in and out, so callers never share state with it.
+type MemoryCourseRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryCourseRepository(store *MemoryStore) port.CourseRepositoryPort {
+	return &MemoryCourseRepository{store: store}
+}
+
+func (r *MemoryCourseRepository) CreateCourse(ctx context.Context, course *model.Course) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	course.TenantID = tenantID
+
+	return r.store.write(ctx, func() (func(), error) {
+		if _, ok := r.store.courses[course.ID]; ok {
+			return nil, fault.New("failed to insert course, id already exists",
+				fault.WithCode(fault.Internal),
+				fault.WithContext("id", course.ID),
+			)
+		}
+
+		r.store.courses[course.ID] = *course
+		return func() { delete(r.store.courses, course.ID) }, nil
+	})
+}
+
+func (r *MemoryCourseRepository) GetCourseByID(ctx context.Context, id string) (*model.Course, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	course, ok := r.store.courses[id]
+	if !ok || course.TenantID != tenantID {
+		return nil, model.ErrCourseNotFound
+	}
+
+	return &course, nil
+}
+
+func (r *MemoryCourseRepository) GetCoursesByIDs(ctx context.Context, ids []string) ([]*model.Course, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	courses := []*model.Course{}
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		course, ok := r.store.courses[id]
+		if !ok || course.TenantID != tenantID || seen[id] {
+			continue
+		}
+		seen[id] = true
+		courses = append(courses, &course)
+	}
+
+	return courses, nil
+}
+
+func (r *MemoryCourseRepository) ListCourses(ctx context.Context, limit int) ([]*model.Course, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 0 {
+		return nil, fault.New("failed to list courses, limit must not be negative",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("limit", limit),
+		)
+	}
+
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	courses := []*model.Course{}
+	for _, course := range r.store.courses {
+		if course.TenantID == tenantID {
+			courses = append(courses, &course)
+		}
+	}
+	r.store.mu.RUnlock()
+
+	slices.SortFunc(courses, func(a, b *model.Course) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return courses[:min(limit, len(courses))], nil
+}
+
+func (r *MemoryCourseRepository) DeleteCourseByID(ctx context.Context, id string) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.store.write(ctx, func() (func(), error) {
+		course, ok := r.store.courses[id]
+		if !ok || course.TenantID != tenantID {
+			return nil, model.ErrCourseNotFound
+		}
+
+		revisions := r.store.revisions[id]
+		delete(r.store.courses, id)
+		delete(r.store.revisions, id)
+
+		return func() {
+			r.store.courses[id] = course
+			r.store.revisions[id] = revisions
+		}, nil
+	})
+}
+
+func (r *MemoryCourseRepository) UpdateCourse(ctx context.Context, course *model.Course) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	course.TenantID = tenantID
+
+	return r.store.write(ctx, func() (func(), error) {
+		before, ok := r.store.courses[course.ID]
+		if !ok || before.TenantID != tenantID {
+			return nil, model.ErrCourseNotFound
+		}
+
+		updated := before
+		updated.Title = course.Title
+		updated.Description = course.Description
+		r.store.courses[course.ID] = updated
+
+		return func() { r.store.courses[course.ID] = before }, nil
+	})
+}
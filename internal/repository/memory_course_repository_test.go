@@ -0,0 +1,78 @@
+//go:build unit
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/internal/repository/repositorytest"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+func newMemoryCourse(t *testing.T, title string) *model.Course {
+	t.Helper()
+
+	course, err := model.NewCourse(model.NewCourseInput{
+		Title:       title,
+		Description: "Kept in memory.",
+	})
+	require.NoError(t, err)
+	return course
+}
+
+func TestMemoryCourseRepository_Contract(t *testing.T) {
+	repositorytest.RunCourseRepository(t, func(t *testing.T) port.CourseRepositoryPort {
+		return NewMemoryCourseRepository(NewMemoryStore())
+	})
+}
+
+func TestMemoryCourseRepository_Transaction(t *testing.T) {
+	store := NewMemoryStore()
+	repo := NewMemoryCourseRepository(store)
+	revisions := NewMemoryCourseRevisionRepository(store)
+	transactor := db.NewMemoryTransactor()
+	ctx := tenant.WithID(context.Background(), "school-a")
+
+	kept := newMemoryCourse(t, "Kept")
+	require.NoError(t, repo.CreateCourse(ctx, kept))
+	require.NoError(t, revisions.CreateCourseRevision(ctx, model.NewCourseRevision(kept, "tester", nil)))
+
+	t.Run("should undo every write when the transaction fails", func(t *testing.T) {
+		created := newMemoryCourse(t, "Rolled back")
+
+		err := transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+			if err := repo.CreateCourse(ctx, created); err != nil {
+				return err
+			}
+			if err := repo.DeleteCourseByID(ctx, kept.ID); err != nil {
+				return err
+			}
+			return repo.DeleteCourseByID(ctx, "0199a000-0000-7000-8000-000000000000")
+		})
+		require.ErrorIs(t, err, model.ErrCourseNotFound)
+
+		_, err = repo.GetCourseByID(ctx, created.ID)
+		require.ErrorIs(t, err, model.ErrCourseNotFound)
+
+		_, err = repo.GetCourseByID(ctx, kept.ID)
+		require.NoError(t, err)
+
+		history, err := revisions.ListCourseRevisions(ctx, kept.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 1, "revisions deleted in cascade must come back")
+	})
+
+	t.Run("should remove revisions along with their course", func(t *testing.T) {
+		require.NoError(t, repo.DeleteCourseByID(ctx, kept.ID))
+
+		history, err := revisions.ListCourseRevisions(ctx, kept.ID)
+		require.NoError(t, err)
+		require.Empty(t, history)
+	})
+}
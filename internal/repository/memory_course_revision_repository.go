@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+type MemoryCourseRevisionRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryCourseRevisionRepository(store *MemoryStore) port.CourseRevisionRepositoryPort {
+	return &MemoryCourseRevisionRepository{store: store}
+}
+
+// CreateCourseRevision stores revision with the next number of its course and
+// sets revision.Revision. Like the foreign key in Postgres, it fails when the
+// course does not exist.
+func (r *MemoryCourseRevisionRepository) CreateCourseRevision(ctx context.Context, revision *model.CourseRevision) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	revision.TenantID = tenantID
+
+	return r.store.write(ctx, func() (func(), error) {
+		if _, ok := r.store.courses[revision.CourseID]; !ok {
+			return nil, fault.New("failed to insert course revision, course does not exist",
+				fault.WithCode(fault.Internal),
+				fault.WithContext("course_id", revision.CourseID),
+			)
+		}
+
+		revisions := r.store.revisions[revision.CourseID]
+		revision.Revision = 1
+		if n := len(revisions); n > 0 {
+			revision.Revision = revisions[n-1].Revision + 1
+		}
+		r.store.revisions[revision.CourseID] = append(revisions, *revision)
+
+		return func() { r.store.revisions[revision.CourseID] = revisions }, nil
+	})
+}
+
+func (r *MemoryCourseRevisionRepository) ListCourseRevisions(ctx context.Context, courseID string) ([]*model.CourseRevision, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stored := r.store.revisions[courseID]
+	revisions := make([]*model.CourseRevision, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		if rev := stored[i]; rev.TenantID == tenantID {
+			revisions = append(revisions, &rev)
+		}
+	}
+
+	return revisions, nil
+}
+
+func (r *MemoryCourseRevisionRepository) GetCourseRevision(ctx context.Context, courseID string, revision int) (*model.CourseRevision, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, rev := range r.store.revisions[courseID] {
+		if rev.Revision == revision && rev.TenantID == tenantID {
+			return &rev, nil
+		}
+	}
+
+	return nil, model.ErrRevisionNotFound
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+)
+
+// MemoryStore holds the tables of the memory driver. The memory repositories
+// share one store so they keep the constraints the schema enforces in
+// Postgres, such as revisions being removed along with their course.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	courses   map[string]model.Course
+	revisions map[string][]model.CourseRevision
+	audit     []model.AuditEntry
+	apiKeys   map[string]model.APIKey
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		courses:   make(map[string]model.Course),
+		revisions: make(map[string][]model.CourseRevision),
+		apiKeys:   make(map[string]model.APIKey),
+	}
+}
+
+// write runs fn under the store lock. When ctx carries a transaction, the
+// undo func returned by fn is registered to run, under the same lock, if the
+// transaction rolls back.
+func (s *MemoryStore) write(ctx context.Context, fn func() (undo func(), err error)) error {
+	if err := alive(ctx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	undo, err := fn()
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if undo != nil {
+		db.OnRollback(ctx, func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			undo()
+		})
+	}
+	return nil
+}
+
+// alive fails, like a database driver would, when ctx is done before the
+// operation starts.
+func alive(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fault.Wrap(err,
+			"context done before the operation ran",
+			fault.WithCode(fault.Internal),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+func TestAPIKeyRepository_Integration(t *testing.T) {
+	require.NotNil(t, testDB, "database connection should not be nil")
+
+	repo := NewPostgresAPIKeyRepository(testDB, testDBConfig)
+	ctx := tenant.WithID(context.Background(), "school-a")
+	otherTenant := tenant.WithID(context.Background(), "school-b")
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Microsecond)
+	key, _, err := model.NewAPIKey(model.NewAPIKeyInput{
+		Name:      "integration",
+		Scopes:    []string{"course:read", "course:create"},
+		ExpiresAt: &expiresAt,
+	})
+	require.NoError(t, err)
+
+	t.Run("Create", func(t *testing.T) {
+		require.NoError(t, repo.CreateAPIKey(ctx, key))
+	})
+
+	t.Run("GetByPrefix", func(t *testing.T) {
+		got, err := repo.GetAPIKeyByPrefix(ctx, key.Prefix)
+		require.NoError(t, err)
+		require.Equal(t, key.ID, got.ID)
+		require.Equal(t, "school-a", got.TenantID)
+		require.Equal(t, key.KeyHash, got.KeyHash)
+		require.Equal(t, key.Scopes, got.Scopes)
+		require.True(t, expiresAt.Equal(*got.ExpiresAt))
+		require.Nil(t, got.LastUsedAt)
+	})
+
+	t.Run("Touch", func(t *testing.T) {
+		require.NoError(t, repo.TouchAPIKey(tenant.WithID(context.Background(), "school-b"), key.ID, time.Now()))
+		got, err := repo.GetAPIKeyByID(ctx, key.ID)
+		require.NoError(t, err)
+		require.Nil(t, got.LastUsedAt, "keys of other tenants are not touched")
+
+		require.NoError(t, repo.TouchAPIKey(ctx, key.ID, time.Now()))
+
+		got, err = repo.GetAPIKeyByID(ctx, key.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got.LastUsedAt)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		keys, err := repo.ListAPIKeys(ctx)
+		require.NoError(t, err)
+		require.NotEmpty(t, keys)
+
+		keys, err = repo.ListAPIKeys(otherTenant)
+		require.NoError(t, err)
+		for _, k := range keys {
+			require.NotEqual(t, key.ID, k.ID)
+		}
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		require.ErrorIs(t, repo.RevokeAPIKey(otherTenant, key.ID, time.Now()), model.ErrAPIKeyNotFound)
+		require.NoError(t, repo.RevokeAPIKey(ctx, key.ID, time.Now()))
+		require.ErrorIs(t, repo.RevokeAPIKey(ctx, key.ID, time.Now()), model.ErrAPIKeyNotFound)
+
+		got, err := repo.GetAPIKeyByID(ctx, key.ID)
+		require.NoError(t, err)
+		require.True(t, got.IsRevoked())
+	})
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+const auditColumns = `id, tenant_id, course_id, action, actor, request_id, before, after, changes, co_actors, occurred_at`
+
+// PostgresAuditRepository only ever inserts into course_audit_log; the table
+// rejects updates and deletes.
+type PostgresAuditRepository struct {
+	db  *sqlx.DB
+	cfg *config.DBConfig
+}
+
+func NewPostgresAuditRepository(db *sqlx.DB, cfg *config.DBConfig) port.AuditRepositoryPort {
+	return &PostgresAuditRepository{db: db, cfg: cfg}
+}
+
+func (r *PostgresAuditRepository) AppendAuditEntry(ctx context.Context, entry *model.AuditEntry) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	entry.TenantID = tenantID
+
+	query := `
+		INSERT INTO course_audit_log (` + auditColumns + `)
+		VALUES (:id, :tenant_id, :course_id, :action, :actor, :request_id, :before, :after, :changes, :co_actors, :occurred_at)
+	`
+
+	if _, err := db.Bounded(r.db, r.cfg).NamedExecContext(ctx, query, entry); err != nil {
+		return fault.Wrap(err,
+			"failed to insert audit entry into database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return nil
+}
+
+func (r *PostgresAuditRepository) ListAuditEntries(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []string{"tenant_id = $1"}
+	args := []any{tenantID}
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.CourseID != "" {
+		where("course_id = $%d", filter.CourseID)
+	}
+	if filter.Actor != "" {
+		where("actor = $%d", filter.Actor)
+	}
+	if filter.From != nil {
+		where("occurred_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		where("occurred_at < $%d", *filter.To)
+	}
+	if filter.Cursor != "" {
+		// Ids are UUIDv7, so they sort in insertion order.
+		where("id < $%d", filter.Cursor)
+	}
+	args = append(args, filter.Limit)
+
+	query := fmt.Sprintf(`SELECT %s FROM course_audit_log WHERE %s ORDER BY id DESC LIMIT $%d`,
+		auditColumns, strings.Join(conditions, " AND "), len(args))
+
+	entries := []*model.AuditEntry{}
+	if err := db.Bounded(r.db, r.cfg).SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, fault.Wrap(err,
+			"failed to list audit entries from database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return entries, nil
+}
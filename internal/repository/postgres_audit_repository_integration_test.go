@@ -0,0 +1,78 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+func TestAuditRepository_Integration(t *testing.T) {
+	require.NotNil(t, testDB, "database connection should not be nil")
+
+	repo := NewPostgresAuditRepository(testDB, testDBConfig)
+	ctx := tenant.WithID(context.Background(), "school-a")
+
+	course, err := model.NewCourse(model.NewCourseInput{Title: "Audit 101", Description: "Who did what."})
+	require.NoError(t, err)
+	updated := *course
+	updated.Title = "Audit 102"
+
+	created, err := model.NewAuditEntry(model.NewAuditEntryInput{Action: model.AuditCourseCreated, Actor: "user-1", RequestID: "req-1", After: course})
+	require.NoError(t, err)
+	changed, err := model.NewAuditEntry(model.NewAuditEntryInput{Action: model.AuditCourseUpdated, Actor: "user-2", CoActors: []string{"user-3"}, Before: course, After: &updated})
+	require.NoError(t, err)
+
+	t.Run("Append", func(t *testing.T) {
+		require.NoError(t, repo.AppendAuditEntry(ctx, created))
+		require.NoError(t, repo.AppendAuditEntry(ctx, changed))
+	})
+
+	t.Run("List by course", func(t *testing.T) {
+		entries, err := repo.ListAuditEntries(ctx, model.AuditFilter{CourseID: course.ID, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, changed.ID, entries[0].ID)
+		require.Equal(t, "Audit 101", *entries[0].Changes["title"].From)
+		require.Equal(t, model.Actors{"user-3"}, entries[0].CoActors)
+		require.Equal(t, "Audit 102", entries[0].After.Title)
+		require.Nil(t, entries[1].Before)
+		require.Equal(t, "req-1", entries[1].RequestID)
+	})
+
+	t.Run("List by actor, time and cursor", func(t *testing.T) {
+		entries, err := repo.ListAuditEntries(ctx, model.AuditFilter{CourseID: course.ID, Actor: "user-1", Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		future := time.Now().Add(time.Hour)
+		entries, err = repo.ListAuditEntries(ctx, model.AuditFilter{CourseID: course.ID, From: &future, Limit: 10})
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		entries, err = repo.ListAuditEntries(ctx, model.AuditFilter{CourseID: course.ID, Cursor: changed.ID, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, created.ID, entries[0].ID)
+	})
+
+	t.Run("List is tenant scoped", func(t *testing.T) {
+		entries, err := repo.ListAuditEntries(tenant.WithID(context.Background(), "school-b"), model.AuditFilter{CourseID: course.ID, Limit: 10})
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("Append only", func(t *testing.T) {
+		_, err := testDB.ExecContext(ctx, `UPDATE course_audit_log SET actor = 'someone-else' WHERE id = $1`, created.ID)
+		require.Error(t, err)
+
+		_, err = testDB.ExecContext(ctx, `DELETE FROM course_audit_log WHERE id = $1`, created.ID)
+		require.Error(t, err)
+	})
+}
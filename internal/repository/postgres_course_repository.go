@@ -8,25 +8,41 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/marcelofabianov/fault"
 
+	"github.com/marcelofabianov/dojo-go/config"
 	"github.com/marcelofabianov/dojo-go/internal/model"
 	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/telemetry"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
 )
 
+// PostgresCourseRepository scopes every query to the tenant bound to the
+// context; courses of other tenants behave as if they did not exist.
 type PostgresCourseRepository struct {
-	db *sqlx.DB
+	db  *sqlx.DB
+	cfg *config.DBConfig
 }
 
-func NewPostgresCourseRepository(db *sqlx.DB) port.CourseRepositoryPort {
-	return &PostgresCourseRepository{db: db}
+func NewPostgresCourseRepository(db *sqlx.DB, cfg *config.DBConfig) port.CourseRepositoryPort {
+	return &PostgresCourseRepository{db: db, cfg: cfg}
 }
 
-func (r *PostgresCourseRepository) CreateCourse(ctx context.Context, course *model.Course) error {
+func (r *PostgresCourseRepository) CreateCourse(ctx context.Context, course *model.Course) (err error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	course.TenantID = tenantID
+
 	query := `
-		INSERT INTO courses (id, title, description, created_at)
-		VALUES (:id, :title, :description, :created_at)
+		INSERT INTO courses (id, tenant_id, title, description, created_at)
+		VALUES (:id, :tenant_id, :title, :description, :created_at)
 	`
 
-	_, err := r.db.NamedExecContext(ctx, query, course)
+	ctx, span := startQuerySpan(ctx, "PostgresCourseRepository.CreateCourse", "INSERT", query)
+	defer func() { telemetry.End(span, err) }()
+
+	_, err = db.Bounded(r.db, r.cfg).NamedExecContext(ctx, query, course)
 	if err != nil {
 		return fault.Wrap(err,
 			"failed to insert course into database",
@@ -37,15 +53,23 @@ func (r *PostgresCourseRepository) CreateCourse(ctx context.Context, course *mod
 	return nil
 }
 
-func (r *PostgresCourseRepository) GetCourseByID(ctx context.Context, id string) (*model.Course, error) {
+func (r *PostgresCourseRepository) GetCourseByID(ctx context.Context, id string) (_ *model.Course, err error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT id, title, description, created_at
+		SELECT id, tenant_id, title, description, created_at
 		FROM courses
-		WHERE id = $1
+		WHERE id = $1 AND tenant_id = $2
 	`
 
+	ctx, span := startQuerySpan(ctx, "PostgresCourseRepository.GetCourseByID", "SELECT", query)
+	defer func() { telemetry.End(span, err) }()
+
 	var course model.Course
-	if err := r.db.GetContext(ctx, &course, query, id); err != nil {
+	if err := db.Bounded(r.db, r.cfg).GetContext(ctx, &course, query, id, tenantID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, model.ErrCourseNotFound
 		}
@@ -58,10 +82,77 @@ func (r *PostgresCourseRepository) GetCourseByID(ctx context.Context, id string)
 	return &course, nil
 }
 
-func (r *PostgresCourseRepository) DeleteCourseByID(ctx context.Context, id string) error {
-	query := `DELETE FROM courses WHERE id = $1`
+func (r *PostgresCourseRepository) GetCoursesByIDs(ctx context.Context, ids []string) (_ []*model.Course, err error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	courses := []*model.Course{}
+	if len(ids) == 0 {
+		return courses, nil
+	}
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	query := `
+		SELECT id, tenant_id, title, description, created_at
+		FROM courses
+		WHERE id = ANY($1::uuid[]) AND tenant_id = $2
+	`
+
+	ctx, span := startQuerySpan(ctx, "PostgresCourseRepository.GetCoursesByIDs", "SELECT", query)
+	defer func() { telemetry.End(span, err) }()
+
+	if err := db.Bounded(r.db, r.cfg).SelectContext(ctx, &courses, query, ids, tenantID); err != nil {
+		return nil, fault.Wrap(err,
+			"failed to get courses by ids from database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return courses, nil
+}
+
+// ListCourses returns the newest courses of the tenant, at most limit of them.
+func (r *PostgresCourseRepository) ListCourses(ctx context.Context, limit int) (_ []*model.Course, err error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, tenant_id, title, description, created_at
+		FROM courses
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2
+	`
+
+	ctx, span := startQuerySpan(ctx, "PostgresCourseRepository.ListCourses", "SELECT", query)
+	defer func() { telemetry.End(span, err) }()
+
+	courses := []*model.Course{}
+	if err := db.Bounded(r.db, r.cfg).SelectContext(ctx, &courses, query, tenantID, limit); err != nil {
+		return nil, fault.Wrap(err,
+			"failed to list courses from database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return courses, nil
+}
+
+func (r *PostgresCourseRepository) DeleteCourseByID(ctx context.Context, id string) (err error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM courses WHERE id = $1 AND tenant_id = $2`
+
+	ctx, span := startQuerySpan(ctx, "PostgresCourseRepository.DeleteCourseByID", "DELETE", query)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := db.Bounded(r.db, r.cfg).ExecContext(ctx, query, id, tenantID)
 	if err != nil {
 		return fault.Wrap(err,
 			"failed to delete course by id from database",
@@ -84,13 +175,23 @@ func (r *PostgresCourseRepository) DeleteCourseByID(ctx context.Context, id stri
 	return nil
 }
 
-func (r *PostgresCourseRepository) UpdateCourse(ctx context.Context, course *model.Course) error {
+func (r *PostgresCourseRepository) UpdateCourse(ctx context.Context, course *model.Course) (err error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	course.TenantID = tenantID
+
 	query := `
 		UPDATE courses
 		SET title = :title, description = :description
-		WHERE id = :id
+		WHERE id = :id AND tenant_id = :tenant_id
 	`
-	result, err := r.db.NamedExecContext(ctx, query, course)
+
+	ctx, span := startQuerySpan(ctx, "PostgresCourseRepository.UpdateCourse", "UPDATE", query)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := db.Bounded(r.db, r.cfg).NamedExecContext(ctx, query, course)
 	if err != nil {
 		return fault.Wrap(err,
 			"failed to update course in database",
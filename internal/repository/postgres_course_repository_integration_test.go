@@ -17,11 +17,17 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
 
+	"github.com/marcelofabianov/dojo-go/config"
 	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/internal/repository/repositorytest"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
 )
 
 var (
-	db *sqlx.DB
+	testDB       *sqlx.DB
+	testDBConfig = &config.DBConfig{QueryTimeout: 5 * time.Second, ExecTimeout: 5 * time.Second}
 )
 
 func TestMain(m *testing.M) {
@@ -56,14 +62,14 @@ func TestMain(m *testing.M) {
 		log.Fatalf("could not get connection string: %s", err)
 	}
 
-	db, err = sqlx.Connect("pgx", connStr)
+	testDB, err = sqlx.Connect("pgx", connStr)
 	if err != nil {
 		log.Fatalf("could not connect to database: %s", err)
 	}
 
 	// 3. Rodar as migrations
 	goose.SetBaseFS(os.DirFS("../../"))
-	if err := goose.Up(db.DB, "db/migrations"); err != nil {
+	if err := goose.Up(testDB.DB, "db/migrations"); err != nil {
 		log.Fatalf("could not run migrations: %s", err)
 	}
 
@@ -74,10 +80,10 @@ func TestMain(m *testing.M) {
 }
 
 func TestCourseRepository_Integration(t *testing.T) {
-	require.NotNil(t, db, "database connection should not be nil")
+	require.NotNil(t, testDB, "database connection should not be nil")
 
-	repo := NewPostgresCourseRepository(db)
-	ctx := context.Background()
+	repo := NewPostgresCourseRepository(testDB, testDBConfig)
+	ctx := tenant.WithID(context.Background(), "school-a")
 
 	newCourse, err := model.NewCourse(model.NewCourseInput{
 		Title:       "Integration Testing 101",
@@ -120,3 +126,91 @@ func TestCourseRepository_Integration(t *testing.T) {
 		require.ErrorIs(t, err, model.ErrCourseNotFound)
 	})
 }
+
+func TestCourseRepository_Contract_Integration(t *testing.T) {
+	require.NotNil(t, testDB, "database connection should not be nil")
+
+	repositorytest.RunCourseRepository(t, func(t *testing.T) port.CourseRepositoryPort {
+		return NewPostgresCourseRepository(testDB, testDBConfig)
+	})
+}
+
+func TestCourseRepository_Transaction_Integration(t *testing.T) {
+	require.NotNil(t, testDB, "database connection should not be nil")
+
+	repo := NewPostgresCourseRepository(testDB, testDBConfig)
+	transactor := db.NewTransactor(testDB)
+	ctx := tenant.WithID(context.Background(), "school-a")
+
+	newCourse, err := model.NewCourse(model.NewCourseInput{
+		Title:       "Transactions 101",
+		Description: "Rolled back on failure.",
+	})
+	require.NoError(t, err)
+
+	err = transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+		if err := repo.CreateCourse(ctx, newCourse); err != nil {
+			return err
+		}
+		return repo.DeleteCourseByID(ctx, "0199a000-0000-7000-8000-000000000000")
+	})
+	require.ErrorIs(t, err, model.ErrCourseNotFound)
+
+	_, err = repo.GetCourseByID(ctx, newCourse.ID)
+	require.ErrorIs(t, err, model.ErrCourseNotFound)
+}
+
+func TestCourseRepository_TenantIsolation_Integration(t *testing.T) {
+	require.NotNil(t, testDB, "database connection should not be nil")
+
+	repo := NewPostgresCourseRepository(testDB, testDBConfig)
+	schoolA := tenant.WithID(context.Background(), "school-a")
+	schoolB := tenant.WithID(context.Background(), "school-b")
+
+	course, err := model.NewCourse(model.NewCourseInput{
+		Title:       "Tenant A only",
+		Description: "Invisible to other tenants.",
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateCourse(schoolA, course))
+
+	t.Run("should not read another tenant's course", func(t *testing.T) {
+		_, err := repo.GetCourseByID(schoolB, course.ID)
+		require.ErrorIs(t, err, model.ErrCourseNotFound)
+	})
+
+	t.Run("should not update another tenant's course", func(t *testing.T) {
+		hijacked := *course
+		hijacked.Title = "Hijacked"
+		require.ErrorIs(t, repo.UpdateCourse(schoolB, &hijacked), model.ErrCourseNotFound)
+
+		got, err := repo.GetCourseByID(schoolA, course.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Tenant A only", got.Title)
+	})
+
+	t.Run("should not delete another tenant's course", func(t *testing.T) {
+		require.ErrorIs(t, repo.DeleteCourseByID(schoolB, course.ID), model.ErrCourseNotFound)
+
+		_, err := repo.GetCourseByID(schoolA, course.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("should not list another tenant's courses", func(t *testing.T) {
+		courses, err := repo.ListCourses(schoolB, 100)
+		require.NoError(t, err)
+		for _, c := range courses {
+			require.NotEqual(t, course.ID, c.ID)
+		}
+
+		courses, err = repo.ListCourses(schoolA, 100)
+		require.NoError(t, err)
+		require.NotEmpty(t, courses)
+	})
+
+	t.Run("should refuse to query without a tenant", func(t *testing.T) {
+		_, err := repo.GetCourseByID(context.Background(), course.ID)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, model.ErrCourseNotFound)
+	})
+}
@@ -0,0 +1,79 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+func TestCourseRevisionRepository_Integration(t *testing.T) {
+	require.NotNil(t, testDB, "database connection should not be nil")
+
+	courses := NewPostgresCourseRepository(testDB, testDBConfig)
+	repo := NewPostgresCourseRevisionRepository(testDB, testDBConfig)
+	ctx := tenant.WithID(context.Background(), "school-a")
+
+	course, err := model.NewCourse(model.NewCourseInput{Title: "History 101", Description: "What it looked like."})
+	require.NoError(t, err)
+	require.NoError(t, courses.CreateCourse(ctx, course))
+
+	first := model.NewCourseRevision(course, "user-1", nil)
+	course.Title = "History 102"
+	second := model.NewCourseRevision(course, "user-2", nil)
+	restored := model.NewCourseRevision(course, "user-1", &first.Revision)
+
+	t.Run("Create numbers revisions per course", func(t *testing.T) {
+		require.NoError(t, repo.CreateCourseRevision(ctx, first))
+		require.NoError(t, repo.CreateCourseRevision(ctx, second))
+		require.NoError(t, repo.CreateCourseRevision(ctx, restored))
+
+		require.Equal(t, 1, first.Revision)
+		require.Equal(t, 2, second.Revision)
+		require.Equal(t, 3, restored.Revision)
+	})
+
+	t.Run("List newest first", func(t *testing.T) {
+		revisions, err := repo.ListCourseRevisions(ctx, course.ID)
+		require.NoError(t, err)
+		require.Len(t, revisions, 3)
+		require.Equal(t, 3, revisions[0].Revision)
+		require.Equal(t, 1, *revisions[0].RollbackOf)
+		require.Equal(t, "History 101", revisions[2].Title)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		revision, err := repo.GetCourseRevision(ctx, course.ID, 2)
+		require.NoError(t, err)
+		require.Equal(t, "History 102", revision.Title)
+		require.Equal(t, "user-2", revision.Actor)
+		require.Nil(t, revision.RollbackOf)
+
+		_, err = repo.GetCourseRevision(ctx, course.ID, 4)
+		require.ErrorIs(t, err, model.ErrRevisionNotFound)
+	})
+
+	t.Run("Tenant scoped", func(t *testing.T) {
+		other := tenant.WithID(context.Background(), "school-b")
+
+		revisions, err := repo.ListCourseRevisions(other, course.ID)
+		require.NoError(t, err)
+		require.Empty(t, revisions)
+
+		_, err = repo.GetCourseRevision(other, course.ID, 1)
+		require.ErrorIs(t, err, model.ErrRevisionNotFound)
+	})
+
+	t.Run("Deleted with the course", func(t *testing.T) {
+		require.NoError(t, courses.DeleteCourseByID(ctx, course.ID))
+
+		revisions, err := repo.ListCourseRevisions(ctx, course.ID)
+		require.NoError(t, err)
+		require.Empty(t, revisions)
+	})
+}
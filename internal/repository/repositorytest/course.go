@@ -0,0 +1,319 @@
+// Package repositorytest holds conformance suites that every adapter of a
+// repository port must pass, whatever the storage behind it.
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+// CourseRepositoryFactory returns the repository under test. It is called
+// once per subtest; adapters backed by a shared database may return
+// repositories over the same data, as every subtest works in a tenant of its
+// own.
+type CourseRepositoryFactory func(t *testing.T) port.CourseRepositoryPort
+
+// concurrentWriters is how many goroutines write at once in the concurrency
+// checks.
+const concurrentWriters = 20
+
+// RunCourseRepository checks that the repositories built by factory behave
+// like a CourseRepositoryPort: CRUD, batch reads, tenant scoping, not-found errors,
+// concurrent writes and cancelled contexts.
+func RunCourseRepository(t *testing.T, factory CourseRepositoryFactory) {
+	t.Helper()
+
+	t.Run("should create and get a course", func(t *testing.T) {
+		repo, ctx := factory(t), newTenant(t)
+		course := newCourse(t, "Contract 101")
+
+		require.NoError(t, repo.CreateCourse(ctx, course))
+
+		got, err := repo.GetCourseByID(ctx, course.ID)
+		require.NoError(t, err)
+		assert.Equal(t, course.ID, got.ID)
+		assert.Equal(t, course.Title, got.Title)
+		assert.Equal(t, course.Description, got.Description)
+		assert.Equal(t, course.TenantID, got.TenantID)
+		assert.WithinDuration(t, course.CreatedAt, got.CreatedAt, time.Millisecond)
+	})
+
+	t.Run("should not share state with callers", func(t *testing.T) {
+		repo, ctx := factory(t), newTenant(t)
+		course := newCourse(t, "Contract 101")
+		require.NoError(t, repo.CreateCourse(ctx, course))
+
+		course.Title = "Changed after create"
+		got, err := repo.GetCourseByID(ctx, course.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Contract 101", got.Title)
+
+		got.Title = "Changed after get"
+		again, err := repo.GetCourseByID(ctx, course.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Contract 101", again.Title)
+	})
+
+	t.Run("should reject a duplicate id", func(t *testing.T) {
+		repo, ctx := factory(t), newTenant(t)
+		course := newCourse(t, "Contract 101")
+		require.NoError(t, repo.CreateCourse(ctx, course))
+
+		err := repo.CreateCourse(ctx, newCourseWithID(t, course.ID, "Duplicate"))
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, model.ErrCourseNotFound)
+
+		got, err := repo.GetCourseByID(ctx, course.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Contract 101", got.Title)
+	})
+
+	t.Run("should update title and description only", func(t *testing.T) {
+		repo, ctx := factory(t), newTenant(t)
+		course := newCourse(t, "Contract 101")
+		require.NoError(t, repo.CreateCourse(ctx, course))
+
+		changed := *course
+		changed.Title = "Contract 102"
+		changed.Description = "Updated."
+		changed.CreatedAt = course.CreatedAt.Add(time.Hour)
+		require.NoError(t, repo.UpdateCourse(ctx, &changed))
+
+		got, err := repo.GetCourseByID(ctx, course.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Contract 102", got.Title)
+		assert.Equal(t, "Updated.", got.Description)
+		assert.WithinDuration(t, course.CreatedAt, got.CreatedAt, time.Millisecond)
+	})
+
+	t.Run("should delete a course", func(t *testing.T) {
+		repo, ctx := factory(t), newTenant(t)
+		course := newCourse(t, "Contract 101")
+		require.NoError(t, repo.CreateCourse(ctx, course))
+
+		require.NoError(t, repo.DeleteCourseByID(ctx, course.ID))
+
+		_, err := repo.GetCourseByID(ctx, course.ID)
+		assert.ErrorIs(t, err, model.ErrCourseNotFound)
+		assert.ErrorIs(t, repo.DeleteCourseByID(ctx, course.ID), model.ErrCourseNotFound)
+		assert.ErrorIs(t, repo.UpdateCourse(ctx, course), model.ErrCourseNotFound)
+	})
+
+	t.Run("should return ErrCourseNotFound for unknown ids", func(t *testing.T) {
+		repo, ctx := factory(t), newTenant(t)
+		missing := newCourse(t, "Never stored")
+
+		_, err := repo.GetCourseByID(ctx, missing.ID)
+		assert.ErrorIs(t, err, model.ErrCourseNotFound)
+		assert.ErrorIs(t, repo.UpdateCourse(ctx, missing), model.ErrCourseNotFound)
+		assert.ErrorIs(t, repo.DeleteCourseByID(ctx, missing.ID), model.ErrCourseNotFound)
+	})
+
+	t.Run("should hide the courses of other tenants", func(t *testing.T) {
+		repo := factory(t)
+		owner, other := newTenant(t), newTenant(t)
+		course := newCourse(t, "Owner only")
+		require.NoError(t, repo.CreateCourse(owner, course))
+
+		_, err := repo.GetCourseByID(other, course.ID)
+		assert.ErrorIs(t, err, model.ErrCourseNotFound)
+
+		hijacked := *course
+		hijacked.Title = "Hijacked"
+		assert.ErrorIs(t, repo.UpdateCourse(other, &hijacked), model.ErrCourseNotFound)
+		assert.ErrorIs(t, repo.DeleteCourseByID(other, course.ID), model.ErrCourseNotFound)
+
+		courses, err := repo.ListCourses(other, 10)
+		require.NoError(t, err)
+		assert.Empty(t, courses)
+
+		got, err := repo.GetCourseByID(owner, course.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Owner only", got.Title)
+	})
+
+	t.Run("should refuse to work without a tenant", func(t *testing.T) {
+		repo, ctx := factory(t), context.Background()
+		course := newCourse(t, "No tenant")
+
+		err := repo.CreateCourse(ctx, course)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, model.ErrCourseNotFound)
+
+		_, err = repo.GetCourseByID(ctx, course.ID)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, model.ErrCourseNotFound)
+
+		_, err = repo.ListCourses(ctx, 10)
+		assert.Error(t, err)
+	})
+
+	t.Run("should list the newest courses first up to the limit", func(t *testing.T) {
+		repo, ctx := factory(t), newTenant(t)
+		start := time.Now().Add(-time.Hour)
+
+		ids := make([]string, 3)
+		for i := range ids {
+			course := newCourse(t, fmt.Sprintf("Course %d", i))
+			course.CreatedAt = start.Add(time.Duration(i) * time.Minute)
+			require.NoError(t, repo.CreateCourse(ctx, course))
+			ids[i] = course.ID
+		}
+
+		courses, err := repo.ListCourses(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, courses, 3)
+		assert.Equal(t, []string{ids[2], ids[1], ids[0]}, courseIDs(courses))
+
+		courses, err = repo.ListCourses(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[2], ids[1]}, courseIDs(courses))
+
+		courses, err = repo.ListCourses(ctx, 0)
+		require.NoError(t, err)
+		assert.Empty(t, courses)
+	})
+
+	t.Run("should get many courses at once", func(t *testing.T) {
+		repo := factory(t)
+		ctx, other := newTenant(t), newTenant(t)
+		first, second := newCourse(t, "First"), newCourse(t, "Second")
+		require.NoError(t, repo.CreateCourse(ctx, first))
+		require.NoError(t, repo.CreateCourse(ctx, second))
+		foreign := newCourse(t, "Foreign")
+		require.NoError(t, repo.CreateCourse(other, foreign))
+		missing := newCourse(t, "Never stored")
+
+		courses, err := repo.GetCoursesByIDs(ctx, []string{second.ID, missing.ID, first.ID, foreign.ID, second.ID})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{first.ID, second.ID}, courseIDs(courses))
+
+		courses, err = repo.GetCoursesByIDs(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, courses)
+
+		_, err = repo.GetCoursesByIDs(context.Background(), []string{first.ID})
+		assert.Error(t, err)
+	})
+
+	t.Run("should keep every concurrent create", func(t *testing.T) {
+		repo, ctx := factory(t), newTenant(t)
+
+		var wg sync.WaitGroup
+		for i := range concurrentWriters {
+			wg.Go(func() {
+				course, err := model.NewCourse(model.NewCourseInput{
+					Title:       fmt.Sprintf("Concurrent %d", i),
+					Description: "Created concurrently.",
+				})
+				if assert.NoError(t, err) {
+					assert.NoError(t, repo.CreateCourse(ctx, course))
+				}
+			})
+		}
+		wg.Wait()
+
+		courses, err := repo.ListCourses(ctx, concurrentWriters*2)
+		require.NoError(t, err)
+		assert.Len(t, courses, concurrentWriters)
+	})
+
+	t.Run("should apply one of the concurrent updates", func(t *testing.T) {
+		repo, ctx := factory(t), newTenant(t)
+		course := newCourse(t, "Contended")
+		require.NoError(t, repo.CreateCourse(ctx, course))
+
+		titles := make(map[string]bool, concurrentWriters)
+		for i := range concurrentWriters {
+			titles[fmt.Sprintf("Update %d", i)] = true
+		}
+
+		var wg sync.WaitGroup
+		for title := range titles {
+			wg.Go(func() {
+				changed := *course
+				changed.Title = title
+				assert.NoError(t, repo.UpdateCourse(ctx, &changed))
+			})
+		}
+		wg.Wait()
+
+		got, err := repo.GetCourseByID(ctx, course.ID)
+		require.NoError(t, err)
+		assert.True(t, titles[got.Title], "title %q comes from none of the updates", got.Title)
+	})
+
+	t.Run("should stop on a cancelled context", func(t *testing.T) {
+		repo, ctx := factory(t), newTenant(t)
+		stored := newCourse(t, "Stored")
+		require.NoError(t, repo.CreateCourse(ctx, stored))
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		course := newCourse(t, "Cancelled")
+		assert.ErrorIs(t, repo.CreateCourse(cancelled, course), context.Canceled)
+		_, err := repo.GetCourseByID(ctx, course.ID)
+		assert.ErrorIs(t, err, model.ErrCourseNotFound, "a cancelled create must not store the course")
+
+		_, err = repo.GetCourseByID(cancelled, stored.ID)
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = repo.ListCourses(cancelled, 10)
+		assert.ErrorIs(t, err, context.Canceled)
+		_, err = repo.GetCoursesByIDs(cancelled, []string{stored.ID})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		changed := *stored
+		changed.Title = "Changed"
+		assert.ErrorIs(t, repo.UpdateCourse(cancelled, &changed), context.Canceled)
+		assert.ErrorIs(t, repo.DeleteCourseByID(cancelled, stored.ID), context.Canceled)
+
+		got, err := repo.GetCourseByID(ctx, stored.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Stored", got.Title)
+	})
+}
+
+// newTenant returns a context bound to a tenant no other subtest uses.
+func newTenant(t *testing.T) context.Context {
+	t.Helper()
+	return tenant.WithID(context.Background(), "contract-"+uuid.NewString()[24:])
+}
+
+func newCourse(t *testing.T, title string) *model.Course {
+	t.Helper()
+
+	course, err := model.NewCourse(model.NewCourseInput{
+		Title:       title,
+		Description: "Checked by the repository contract.",
+	})
+	require.NoError(t, err)
+	return course
+}
+
+func newCourseWithID(t *testing.T, id, title string) *model.Course {
+	t.Helper()
+
+	course := newCourse(t, title)
+	course.ID = id
+	return course
+}
+
+func courseIDs(courses []*model.Course) []string {
+	ids := make([]string, len(courses))
+	for i, course := range courses {
+		ids[i] = course.ID
+	}
+	return ids
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+type SQLiteAPIKeyRepository struct {
+	db  *sqlx.DB
+	cfg *config.DBConfig
+}
+
+func NewSQLiteAPIKeyRepository(db *sqlx.DB, cfg *config.DBConfig) port.APIKeyRepositoryPort {
+	return &SQLiteAPIKeyRepository{db: db, cfg: cfg}
+}
+
+func (r *SQLiteAPIKeyRepository) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	key.TenantID = tenantID
+
+	query := `
+		INSERT INTO api_keys (id, tenant_id, name, prefix, key_hash, scopes, expires_at, rotated_from, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err = db.Bounded(r.db, r.cfg).ExecContext(ctx, query,
+		key.ID,
+		key.TenantID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		key.Scopes,
+		utcOrNil(key.ExpiresAt),
+		key.RotatedFrom,
+		key.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fault.Wrap(err,
+			"failed to insert api key into database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return nil
+}
+
+func (r *SQLiteAPIKeyRepository) GetAPIKeyByID(ctx context.Context, id string) (*model.APIKey, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.get(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+}
+
+// GetAPIKeyByPrefix is not tenant scoped: it runs during authentication,
+// before the tenant is known, and the key it returns decides the tenant.
+func (r *SQLiteAPIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	return r.get(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE prefix = $1`, prefix)
+}
+
+// get reads from the primary: a replica may not have seen a revocation yet.
+func (r *SQLiteAPIKeyRepository) get(ctx context.Context, query string, args ...any) (*model.APIKey, error) {
+	var key model.APIKey
+	if err := db.Bounded(r.db, r.cfg).GetContext(db.Primary(ctx), &key, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrAPIKeyNotFound
+		}
+		return nil, fault.Wrap(err,
+			"failed to get api key from database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return &key, nil
+}
+
+func (r *SQLiteAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE tenant_id = $1 ORDER BY created_at DESC`
+
+	keys := []*model.APIKey{}
+	if err := db.Bounded(r.db, r.cfg).SelectContext(ctx, &keys, query, tenantID); err != nil {
+		return nil, fault.Wrap(err,
+			"failed to list api keys from database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return keys, nil
+}
+
+func (r *SQLiteAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE api_keys SET revoked_at = $3 WHERE id = $1 AND tenant_id = $2 AND revoked_at IS NULL`
+
+	result, err := db.Bounded(r.db, r.cfg).ExecContext(ctx, query, id, tenantID, revokedAt.UTC())
+	if err != nil {
+		return fault.Wrap(err,
+			"failed to revoke api key in database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fault.Wrap(err,
+			"failed to get rows affected after revoke",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	if rowsAffected == 0 {
+		return model.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *SQLiteAPIKeyRepository) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE api_keys SET last_used_at = $3 WHERE id = $1 AND tenant_id = $2`
+
+	if _, err := db.Bounded(r.db, r.cfg).ExecContext(ctx, query, id, tenantID, usedAt.UTC()); err != nil {
+		return fault.Wrap(err,
+			"failed to update api key last use",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return nil
+}
+
+func utcOrNil(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
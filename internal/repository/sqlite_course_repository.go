@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/telemetry"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+// SQLiteCourseRepository behaves like PostgresCourseRepository on the
+// schema of db/sqlite/migrations. Times are stored in UTC so they sort as
+// text.
+type SQLiteCourseRepository struct {
+	db  *sqlx.DB
+	cfg *config.DBConfig
+}
+
+func NewSQLiteCourseRepository(db *sqlx.DB, cfg *config.DBConfig) port.CourseRepositoryPort {
+	return &SQLiteCourseRepository{db: db, cfg: cfg}
+}
+
+func (r *SQLiteCourseRepository) CreateCourse(ctx context.Context, course *model.Course) (err error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	course.TenantID = tenantID
+
+	query := `
+		INSERT INTO courses (id, tenant_id, title, description, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "SQLiteCourseRepository.CreateCourse", "INSERT", query)
+	defer func() { telemetry.End(span, err) }()
+
+	_, err = db.Bounded(r.db, r.cfg).ExecContext(ctx, query,
+		course.ID,
+		course.TenantID,
+		course.Title,
+		course.Description,
+		course.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fault.Wrap(err,
+			"failed to insert course into database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return nil
+}
+
+func (r *SQLiteCourseRepository) GetCourseByID(ctx context.Context, id string) (_ *model.Course, err error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, tenant_id, title, description, created_at
+		FROM courses
+		WHERE id = $1 AND tenant_id = $2
+	`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "SQLiteCourseRepository.GetCourseByID", "SELECT", query)
+	defer func() { telemetry.End(span, err) }()
+
+	var course model.Course
+	if err := db.Bounded(r.db, r.cfg).GetContext(ctx, &course, query, id, tenantID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrCourseNotFound
+		}
+		return nil, fault.Wrap(err,
+			"failed to get course by id from database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return &course, nil
+}
+
+func (r *SQLiteCourseRepository) GetCoursesByIDs(ctx context.Context, ids []string) (_ []*model.Course, err error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	courses := []*model.Course{}
+	if len(ids) == 0 {
+		return courses, nil
+	}
+
+	// SQLite has no arrays: the ids are bound one placeholder each.
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, tenantID)
+	placeholders := make([]string, len(ids))
+	for i, id := range ids {
+		args = append(args, id)
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+	}
+
+	query := `
+		SELECT id, tenant_id, title, description, created_at
+		FROM courses
+		WHERE tenant_id = $1 AND id IN (` + strings.Join(placeholders, ", ") + `)
+	`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "SQLiteCourseRepository.GetCoursesByIDs", "SELECT", query)
+	defer func() { telemetry.End(span, err) }()
+
+	if err := db.Bounded(r.db, r.cfg).SelectContext(ctx, &courses, query, args...); err != nil {
+		return nil, fault.Wrap(err,
+			"failed to get courses by ids from database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return courses, nil
+}
+
+// ListCourses returns the newest courses of the tenant, at most limit of them.
+func (r *SQLiteCourseRepository) ListCourses(ctx context.Context, limit int) (_ []*model.Course, err error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite reads a negative LIMIT as no limit, where Postgres fails.
+	if limit < 0 {
+		return nil, fault.New("failed to list courses, limit must not be negative",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("limit", limit),
+		)
+	}
+
+	query := `
+		SELECT id, tenant_id, title, description, created_at
+		FROM courses
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2
+	`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "SQLiteCourseRepository.ListCourses", "SELECT", query)
+	defer func() { telemetry.End(span, err) }()
+
+	courses := []*model.Course{}
+	if err := db.Bounded(r.db, r.cfg).SelectContext(ctx, &courses, query, tenantID, limit); err != nil {
+		return nil, fault.Wrap(err,
+			"failed to list courses from database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return courses, nil
+}
+
+func (r *SQLiteCourseRepository) DeleteCourseByID(ctx context.Context, id string) (err error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM courses WHERE id = $1 AND tenant_id = $2`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "SQLiteCourseRepository.DeleteCourseByID", "DELETE", query)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := db.Bounded(r.db, r.cfg).ExecContext(ctx, query, id, tenantID)
+	if err != nil {
+		return fault.Wrap(err,
+			"failed to delete course by id from database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fault.Wrap(err,
+			"failed to get rows affected after delete",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	if rowsAffected == 0 {
+		return model.ErrCourseNotFound
+	}
+
+	return nil
+}
+
+func (r *SQLiteCourseRepository) UpdateCourse(ctx context.Context, course *model.Course) (err error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	course.TenantID = tenantID
+
+	query := `
+		UPDATE courses
+		SET title = :title, description = :description
+		WHERE id = :id AND tenant_id = :tenant_id
+	`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "SQLiteCourseRepository.UpdateCourse", "UPDATE", query)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := db.Bounded(r.db, r.cfg).NamedExecContext(ctx, query, course)
+	if err != nil {
+		return fault.Wrap(err,
+			"failed to update course in database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fault.Wrap(err,
+			"failed to get rows affected after update",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	if rowsAffected == 0 {
+		return model.ErrCourseNotFound
+	}
+
+	return nil
+}
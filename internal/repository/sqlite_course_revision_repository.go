@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+type SQLiteCourseRevisionRepository struct {
+	db  *sqlx.DB
+	cfg *config.DBConfig
+}
+
+func NewSQLiteCourseRevisionRepository(db *sqlx.DB, cfg *config.DBConfig) port.CourseRevisionRepositoryPort {
+	return &SQLiteCourseRevisionRepository{db: db, cfg: cfg}
+}
+
+// CreateCourseRevision stores revision with the next number of its course and
+// sets revision.Revision. Transactions take the write lock when they begin,
+// so concurrent numbering is serialized.
+func (r *SQLiteCourseRevisionRepository) CreateCourseRevision(ctx context.Context, revision *model.CourseRevision) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	revision.TenantID = tenantID
+
+	query := `
+		INSERT INTO course_revisions (` + revisionColumns + `)
+		SELECT $1, $2, COALESCE(MAX(revision), 0) + 1, $3, $4, $5, $6, $7
+		FROM course_revisions
+		WHERE course_id = $1
+		RETURNING revision
+	`
+
+	err = db.Bounded(r.db, r.cfg).GetContext(ctx, &revision.Revision, query,
+		revision.CourseID,
+		revision.TenantID,
+		revision.Title,
+		revision.Description,
+		revision.Actor,
+		revision.RollbackOf,
+		revision.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return fault.Wrap(err,
+			"failed to insert course revision into database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return nil
+}
+
+func (r *SQLiteCourseRevisionRepository) ListCourseRevisions(ctx context.Context, courseID string) ([]*model.CourseRevision, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + revisionColumns + ` FROM course_revisions WHERE course_id = $1 AND tenant_id = $2 ORDER BY revision DESC`
+
+	revisions := []*model.CourseRevision{}
+	if err := db.Bounded(r.db, r.cfg).SelectContext(ctx, &revisions, query, courseID, tenantID); err != nil {
+		return nil, fault.Wrap(err,
+			"failed to list course revisions from database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return revisions, nil
+}
+
+func (r *SQLiteCourseRevisionRepository) GetCourseRevision(ctx context.Context, courseID string, revision int) (*model.CourseRevision, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + revisionColumns + ` FROM course_revisions WHERE course_id = $1 AND tenant_id = $2 AND revision = $3`
+
+	var rev model.CourseRevision
+	if err := db.Bounded(r.db, r.cfg).GetContext(ctx, &rev, query, courseID, tenantID, revision); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrRevisionNotFound
+		}
+		return nil, fault.Wrap(err,
+			"failed to get course revision from database",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return &rev, nil
+}
@@ -0,0 +1,216 @@
+//go:build unit
+
+package repository
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/internal/repository/repositorytest"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/migrate"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+var sqliteTestConfig = &config.DBConfig{QueryTimeout: time.Second, ExecTimeout: time.Second}
+
+// newSQLiteDB opens a migrated database in a temporary file.
+func newSQLiteDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	conn, err := db.NewSQLiteConnection(&config.DBConfig{
+		Path:         filepath.Join(t.TempDir(), "dojo.db"),
+		MaxOpenConns: 4,
+		MaxIdleConns: 4,
+		QueryTimeout: time.Second,
+	}, logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	migrator, err := migrate.New(conn, logger)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up(context.Background()))
+
+	return conn
+}
+
+func TestSQLiteCourseRepository_Contract(t *testing.T) {
+	repositorytest.RunCourseRepository(t, func(t *testing.T) port.CourseRepositoryPort {
+		return NewSQLiteCourseRepository(newSQLiteDB(t), sqliteTestConfig)
+	})
+}
+
+func TestSQLiteCourseRepository_Transaction(t *testing.T) {
+	conn := newSQLiteDB(t)
+	repo := NewSQLiteCourseRepository(conn, sqliteTestConfig)
+	revisions := NewSQLiteCourseRevisionRepository(conn, sqliteTestConfig)
+	transactor := db.NewTransactor(conn)
+	ctx := tenant.WithID(context.Background(), "school-a")
+
+	course, err := model.NewCourse(model.NewCourseInput{Title: "Transactions", Description: "All or nothing."})
+	require.NoError(t, err)
+
+	err = transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+		if err := repo.CreateCourse(ctx, course); err != nil {
+			return err
+		}
+		if err := revisions.CreateCourseRevision(ctx, model.NewCourseRevision(course, "tester", nil)); err != nil {
+			return err
+		}
+		return repo.DeleteCourseByID(ctx, "0199a000-0000-7000-8000-000000000000")
+	})
+	require.ErrorIs(t, err, model.ErrCourseNotFound)
+
+	_, err = repo.GetCourseByID(ctx, course.ID)
+	require.ErrorIs(t, err, model.ErrCourseNotFound)
+
+	t.Run("should number revisions and remove them with their course", func(t *testing.T) {
+		require.NoError(t, repo.CreateCourse(ctx, course))
+		for range 2 {
+			rev := model.NewCourseRevision(course, "tester", nil)
+			require.NoError(t, revisions.CreateCourseRevision(ctx, rev))
+		}
+
+		history, err := revisions.ListCourseRevisions(ctx, course.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		require.Equal(t, 2, history[0].Revision)
+
+		_, err = revisions.GetCourseRevision(ctx, course.ID, 3)
+		require.ErrorIs(t, err, model.ErrRevisionNotFound)
+
+		require.NoError(t, repo.DeleteCourseByID(ctx, course.ID))
+		history, err = revisions.ListCourseRevisions(ctx, course.ID)
+		require.NoError(t, err)
+		require.Empty(t, history)
+	})
+}
+
+func TestSQLiteCourseRepository_ReadReplica(t *testing.T) {
+	primary, replica := newSQLiteDB(t), newSQLiteDB(t)
+	replicas, err := db.OpenReplicas(&config.DBConfig{QueryTimeout: time.Second}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	replicas.Add(replica)
+	replicas.Check(context.Background())
+
+	repo := NewSQLiteCourseRepository(primary, sqliteTestConfig)
+	ctx := tenant.WithID(context.Background(), "school-a")
+
+	course, err := model.NewCourse(model.NewCourseInput{Title: "Replicated", Description: "Read where it was written."})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateCourse(ctx, course))
+
+	t.Run("should read from the replica in a session that has not written", func(t *testing.T) {
+		_, err := repo.GetCourseByID(db.WithSession(ctx, replicas), course.ID)
+		require.ErrorIs(t, err, model.ErrCourseNotFound, "the replica never received the course")
+	})
+
+	t.Run("should read its own writes from the primary", func(t *testing.T) {
+		session := db.WithSession(ctx, replicas)
+		other, err := model.NewCourse(model.NewCourseInput{Title: "Written", Description: "In this session."})
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateCourse(session, other))
+
+		got, err := repo.GetCourseByID(session, course.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Replicated", got.Title)
+	})
+}
+
+func TestSQLiteAuditRepository(t *testing.T) {
+	conn := newSQLiteDB(t)
+	repo := NewSQLiteAuditRepository(conn, sqliteTestConfig)
+	ctx := tenant.WithID(context.Background(), "school-a")
+
+	course, err := model.NewCourse(model.NewCourseInput{Title: "Audit 101", Description: "Who did what."})
+	require.NoError(t, err)
+	updated := *course
+	updated.Title = "Audit 102"
+
+	created, err := model.NewAuditEntry(model.NewAuditEntryInput{Action: model.AuditCourseCreated, Actor: "user-1", After: course})
+	require.NoError(t, err)
+	changed, err := model.NewAuditEntry(model.NewAuditEntryInput{Action: model.AuditCourseUpdated, Actor: "user-2", CoActors: []string{"user-3"}, Before: course, After: &updated})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.AppendAuditEntry(ctx, created))
+	require.NoError(t, repo.AppendAuditEntry(ctx, changed))
+
+	entries, err := repo.ListAuditEntries(ctx, model.AuditFilter{CourseID: course.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, changed.ID, entries[0].ID)
+	require.Equal(t, "Audit 101", *entries[0].Changes["title"].From)
+	require.Equal(t, model.Actors{"user-3"}, entries[0].CoActors)
+	require.Nil(t, entries[1].Before)
+	require.Empty(t, entries[1].CoActors)
+
+	past := time.Now().Add(-time.Hour)
+	entries, err = repo.ListAuditEntries(ctx, model.AuditFilter{From: &past, Actor: "user-1", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	_, err = conn.Exec(`DELETE FROM course_audit_log`)
+	require.ErrorContains(t, err, "append-only")
+}
+
+func TestSQLiteAPIKeyRepository(t *testing.T) {
+	conn := newSQLiteDB(t)
+	repo := NewSQLiteAPIKeyRepository(conn, sqliteTestConfig)
+	ctx := tenant.WithID(context.Background(), "school-a")
+
+	key := &model.APIKey{
+		ID:        "0199a000-0000-7000-8000-000000000001",
+		Name:      "ci",
+		Prefix:    "dojo_abc",
+		KeyHash:   "hash",
+		Scopes:    model.Scopes{"course:read", "course:create"},
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, repo.CreateAPIKey(ctx, key))
+
+	got, err := repo.GetAPIKeyByPrefix(context.Background(), "dojo_abc")
+	require.NoError(t, err)
+	require.Equal(t, "school-a", got.TenantID)
+	require.Equal(t, key.Scopes, got.Scopes)
+	require.Nil(t, got.ExpiresAt)
+
+	other := tenant.WithID(context.Background(), "school-b")
+	require.NoError(t, repo.TouchAPIKey(other, key.ID, time.Now()))
+	got, err = repo.GetAPIKeyByID(ctx, key.ID)
+	require.NoError(t, err)
+	require.Nil(t, got.LastUsedAt, "keys of other tenants are not touched")
+
+	require.NoError(t, repo.TouchAPIKey(ctx, key.ID, time.Now()))
+	require.NoError(t, repo.RevokeAPIKey(ctx, key.ID, time.Now()))
+	require.ErrorIs(t, repo.RevokeAPIKey(ctx, key.ID, time.Now()), model.ErrAPIKeyNotFound)
+
+	got, err = repo.GetAPIKeyByID(ctx, key.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.LastUsedAt)
+	require.NotNil(t, got.RevokedAt)
+
+	_, err = repo.GetAPIKeyByID(tenant.WithID(context.Background(), "school-b"), key.ID)
+	require.ErrorIs(t, err, model.ErrAPIKeyNotFound)
+
+	t.Run("should resolve keys on the primary in a read session", func(t *testing.T) {
+		replicas, err := db.OpenReplicas(&config.DBConfig{QueryTimeout: time.Second}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		require.NoError(t, err)
+		replicas.Add(newSQLiteDB(t))
+		replicas.Check(context.Background())
+
+		got, err := repo.GetAPIKeyByPrefix(db.WithSession(context.Background(), replicas), "dojo_abc")
+		require.NoError(t, err, "the replica never received the key")
+		require.NotNil(t, got.RevokedAt)
+	})
+}
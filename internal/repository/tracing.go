@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/marcelofabianov/dojo-go/internal/repository")
+
+// startQuerySpan starts a client span for a SQL statement. Statements only
+// hold placeholders, so the query text never carries user data.
+func startQuerySpan(ctx context.Context, name, operation, query string) (context.Context, trace.Span) {
+	return startSpan(ctx, semconv.DBSystemNamePostgreSQL, name, operation, query)
+}
+
+func startSQLiteQuerySpan(ctx context.Context, name, operation, query string) (context.Context, trace.Span) {
+	return startSpan(ctx, semconv.DBSystemNameSQLite, name, operation, query)
+}
+
+func startSpan(ctx context.Context, system attribute.KeyValue, name, operation, query string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			system,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(strings.Join(strings.Fields(query), " ")),
+		),
+	)
+}
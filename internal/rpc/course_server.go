@@ -0,0 +1,110 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/marcelofabianov/fault"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	coursev1 "github.com/marcelofabianov/dojo-go/pkg/pb/course/v1"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type CourseServer struct {
+	coursev1.UnimplementedCourseServiceServer
+	courseService port.CourseServicePort
+}
+
+func NewCourseServer(courseService port.CourseServicePort) coursev1.CourseServiceServer {
+	return &CourseServer{courseService: courseService}
+}
+
+func (s *CourseServer) CreateCourse(ctx context.Context, req *coursev1.CreateCourseRequest) (*coursev1.CreateCourseResponse, error) {
+	logger := web.GetLogger(ctx)
+
+	course, err := s.courseService.CreateCourse(ctx, model.NewCourseInput{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+	})
+	if err != nil {
+		logger.Error("failed to create course", "error", err)
+		return nil, toStatus(err)
+	}
+
+	logger.Info("course created successfully", "course_id", course.ID)
+	return &coursev1.CreateCourseResponse{Course: toProto(course)}, nil
+}
+
+func (s *CourseServer) GetCourse(ctx context.Context, req *coursev1.GetCourseRequest) (*coursev1.GetCourseResponse, error) {
+	logger := web.GetLogger(ctx)
+
+	if err := validateID(req.GetId()); err != nil {
+		logger.Warn("invalid uuid format in request", "id", req.GetId())
+		return nil, toStatus(err)
+	}
+
+	course, err := s.courseService.GetCourseByID(ctx, req.GetId())
+	if err != nil {
+		logger.Warn("failed to get course", "id", req.GetId(), "error", err)
+		return nil, toStatus(err)
+	}
+
+	return &coursev1.GetCourseResponse{Course: toProto(course)}, nil
+}
+
+func (s *CourseServer) UpdateCourse(ctx context.Context, req *coursev1.UpdateCourseRequest) (*coursev1.UpdateCourseResponse, error) {
+	logger := web.GetLogger(ctx)
+
+	if err := validateID(req.GetId()); err != nil {
+		logger.Warn("invalid uuid format in request", "id", req.GetId())
+		return nil, toStatus(err)
+	}
+
+	course, err := s.courseService.UpdateCourse(ctx, req.GetId(), model.UpdateCourseInput{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+	})
+	if err != nil {
+		logger.Warn("failed to update course", "id", req.GetId(), "error", err)
+		return nil, toStatus(err)
+	}
+
+	logger.Info("course updated successfully", "course_id", course.ID)
+	return &coursev1.UpdateCourseResponse{Course: toProto(course)}, nil
+}
+
+func (s *CourseServer) DeleteCourse(ctx context.Context, req *coursev1.DeleteCourseRequest) (*coursev1.DeleteCourseResponse, error) {
+	logger := web.GetLogger(ctx)
+
+	if err := validateID(req.GetId()); err != nil {
+		logger.Warn("invalid uuid format in request", "id", req.GetId())
+		return nil, toStatus(err)
+	}
+
+	if err := s.courseService.DeleteCourseByID(ctx, req.GetId()); err != nil {
+		logger.Warn("failed to delete course", "id", req.GetId(), "error", err)
+		return nil, toStatus(err)
+	}
+
+	logger.Info("course deleted successfully", "course_id", req.GetId())
+	return &coursev1.DeleteCourseResponse{}, nil
+}
+
+func validateID(id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return fault.New("invalid id format, must be a valid uuid", fault.WithCode(fault.Invalid))
+	}
+	return nil
+}
+
+func toProto(course *model.Course) *coursev1.Course {
+	return &coursev1.Course{
+		Id:          course.ID,
+		Title:       course.Title,
+		Description: course.Description,
+		CreatedAt:   timestamppb.New(course.CreatedAt),
+	}
+}
@@ -0,0 +1,303 @@
+//go:build unit
+
+package rpc_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/mocks"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/rpc"
+	"github.com/marcelofabianov/dojo-go/internal/service"
+	"github.com/marcelofabianov/dojo-go/pkg/auth"
+	coursev1 "github.com/marcelofabianov/dojo-go/pkg/pb/course/v1"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+func setupServer(t *testing.T) (*grpc.ClientConn, *mocks.MockCourseRepository) {
+	t.Helper()
+	return setupServerWith(t, &config.ServerConfig{Tenancy: config.TenancyConfig{Default: "default"}}, &config.AuthzConfig{}, new(mocks.MockAPIKeyRepository))
+}
+
+func setupServerWith(t *testing.T, cfg *config.ServerConfig, authzCfg *config.AuthzConfig, keys *mocks.MockAPIKeyRepository) (*grpc.ClientConn, *mocks.MockCourseRepository) {
+	t.Helper()
+
+	repo := new(mocks.MockCourseRepository)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	policy := authz.NewPolicy(authzCfg)
+	verifier, err := auth.NewVerifier(cfg)
+	require.NoError(t, err)
+
+	srv := rpc.NewServer(cfg, logger, rpc.NewHealthServer(),
+		rpc.NewCourseServer(service.NewCourseService(repo, mocks.NewCourseRevisionRepositoryStub(), mocks.NewAuditRepositoryStub(), &mocks.FakeTransactor{}, &mocks.FakeMetrics{}, policy)),
+		nil, verifier, service.NewAPIKeyService(keys, &mocks.FakeTransactor{}, policy),
+	)
+
+	lis := bufconn.Listen(1024 * 1024)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, repo
+}
+
+func TestCourseServer(t *testing.T) {
+	ctx := context.Background()
+	courseID := "0199a000-0000-7000-8000-000000000001"
+
+	t.Run("should create and get a course", func(t *testing.T) {
+		conn, repo := setupServer(t)
+		client := coursev1.NewCourseServiceClient(conn)
+		repo.On("CreateCourse", mock.Anything, mock.AnythingOfType("*model.Course")).Return(nil)
+
+		created, err := client.CreateCourse(ctx, &coursev1.CreateCourseRequest{Title: "gRPC", Description: "Protobuf"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, created.GetCourse().GetId())
+		assert.Equal(t, "gRPC", created.GetCourse().GetTitle())
+
+		repo.On("GetCourseByID", mock.Anything, courseID).
+			Return(&model.Course{ID: courseID, Title: "gRPC", CreatedAt: time.Now()}, nil)
+
+		got, err := client.GetCourse(ctx, &coursev1.GetCourseRequest{Id: courseID})
+		require.NoError(t, err)
+		assert.Equal(t, courseID, got.GetCourse().GetId())
+	})
+
+	t.Run("should map not found to codes.NotFound", func(t *testing.T) {
+		conn, repo := setupServer(t)
+		client := coursev1.NewCourseServiceClient(conn)
+		repo.On("GetCourseByID", mock.Anything, courseID).Return(nil, model.ErrCourseNotFound)
+
+		_, err := client.DeleteCourse(ctx, &coursev1.DeleteCourseRequest{Id: courseID})
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.NotFound, st.Code())
+		require.NotEmpty(t, st.Details())
+		assert.Equal(t, "not_found", st.Details()[0].(*errdetails.ErrorInfo).GetReason())
+	})
+
+	t.Run("should map invalid input to codes.InvalidArgument", func(t *testing.T) {
+		conn, repo := setupServer(t)
+		client := coursev1.NewCourseServiceClient(conn)
+
+		_, err := client.GetCourse(ctx, &coursev1.GetCourseRequest{Id: "not-a-uuid"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.CreateCourse(ctx, &coursev1.CreateCourseRequest{Title: "", Description: "d"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		repo.AssertNotCalled(t, "CreateCourse", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should report serving health", func(t *testing.T) {
+		conn, _ := setupServer(t)
+
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+			Service: coursev1.CourseService_ServiceDesc.ServiceName,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	})
+}
+
+func signToken(t *testing.T, secret, tenantID string, roles ...string) string {
+	t.Helper()
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles:  roles,
+		Tenant: tenantID,
+	}).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return signed
+}
+
+func TestServerAuthentication(t *testing.T) {
+	courseID := "0199a000-0000-7000-8000-000000000001"
+	secret := "test-secret"
+
+	cfg := &config.ServerConfig{
+		Auth:    config.AuthConfig{Enabled: true, HMACSecret: secret},
+		Tenancy: config.TenancyConfig{Default: "default"},
+	}
+	authzCfg := &config.AuthzConfig{Enabled: true, Roles: map[string][]string{"viewer": {"course:read"}}}
+
+	token := func(t *testing.T, roles ...string) string {
+		t.Helper()
+		return signToken(t, secret, "default", roles...)
+	}
+
+	t.Run("should reject calls without credentials", func(t *testing.T) {
+		conn, repo := setupServerWith(t, cfg, authzCfg, new(mocks.MockAPIKeyRepository))
+
+		_, err := coursev1.NewCourseServiceClient(conn).DeleteCourse(context.Background(), &coursev1.DeleteCourseRequest{Id: courseID})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		repo.AssertNotCalled(t, "DeleteCourse", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject an invalid bearer token", func(t *testing.T) {
+		conn, _ := setupServerWith(t, cfg, authzCfg, new(mocks.MockAPIKeyRepository))
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer not-a-jwt")
+
+		_, err := coursev1.NewCourseServiceClient(conn).GetCourse(ctx, &coursev1.GetCourseRequest{Id: courseID})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("should apply the roles of the bearer token", func(t *testing.T) {
+		conn, repo := setupServerWith(t, cfg, authzCfg, new(mocks.MockAPIKeyRepository))
+		client := coursev1.NewCourseServiceClient(conn)
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token(t, "viewer"))
+		repo.On("GetCourseByID", mock.Anything, courseID).
+			Return(&model.Course{ID: courseID, Title: "gRPC", CreatedAt: time.Now()}, nil)
+
+		_, err := client.GetCourse(ctx, &coursev1.GetCourseRequest{Id: courseID})
+		require.NoError(t, err)
+
+		_, err = client.DeleteCourse(ctx, &coursev1.DeleteCourseRequest{Id: courseID})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		repo.AssertNotCalled(t, "DeleteCourse", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should apply the scopes of an api key", func(t *testing.T) {
+		key, raw, err := model.NewAPIKey(model.NewAPIKeyInput{Name: "ci", Scopes: []string{"course:read"}})
+		require.NoError(t, err)
+		key.TenantID = "default"
+		now := time.Now()
+		key.LastUsedAt = &now
+
+		keys := new(mocks.MockAPIKeyRepository)
+		keys.On("GetAPIKeyByPrefix", mock.Anything, key.Prefix).Return(key, nil)
+		conn, repo := setupServerWith(t, cfg, authzCfg, keys)
+		client := coursev1.NewCourseServiceClient(conn)
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", raw)
+		repo.On("GetCourseByID", mock.Anything, courseID).
+			Return(&model.Course{ID: courseID, Title: "gRPC", CreatedAt: time.Now()}, nil)
+
+		_, err = client.GetCourse(ctx, &coursev1.GetCourseRequest{Id: courseID})
+		require.NoError(t, err)
+
+		_, err = client.DeleteCourse(ctx, &coursev1.DeleteCourseRequest{Id: courseID})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("should leave health checks open", func(t *testing.T) {
+		conn, _ := setupServerWith(t, cfg, authzCfg, new(mocks.MockAPIKeyRepository))
+
+		_, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+		require.NoError(t, err)
+	})
+}
+
+func TestServerTenancy(t *testing.T) {
+	courseID := "0199a000-0000-7000-8000-000000000001"
+	secret := "test-secret"
+
+	cfg := &config.ServerConfig{
+		Auth:    config.AuthConfig{Enabled: true, HMACSecret: secret},
+		Tenancy: config.TenancyConfig{Default: "default"},
+	}
+	inTenant := func(id string) any {
+		return mock.MatchedBy(func(ctx context.Context) bool {
+			got, _ := tenant.FromContext(ctx)
+			return got == id
+		})
+	}
+	course := &model.Course{ID: courseID, Title: "gRPC", CreatedAt: time.Now()}
+
+	t.Run("should scope calls to the tenant of the credentials", func(t *testing.T) {
+		conn, repo := setupServerWith(t, cfg, &config.AuthzConfig{}, new(mocks.MockAPIKeyRepository))
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+signToken(t, secret, "school-a"))
+		repo.On("GetCourseByID", inTenant("school-a"), courseID).Return(course, nil)
+
+		_, err := coursev1.NewCourseServiceClient(conn).GetCourse(ctx, &coursev1.GetCourseRequest{Id: courseID})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should accept metadata repeating the tenant of the credentials", func(t *testing.T) {
+		conn, repo := setupServerWith(t, cfg, &config.AuthzConfig{}, new(mocks.MockAPIKeyRepository))
+		ctx := metadata.AppendToOutgoingContext(context.Background(),
+			"authorization", "Bearer "+signToken(t, secret, "school-a"),
+			"x-tenant-id", "school-a",
+		)
+		repo.On("GetCourseByID", inTenant("school-a"), courseID).Return(course, nil)
+
+		_, err := coursev1.NewCourseServiceClient(conn).GetCourse(ctx, &coursev1.GetCourseRequest{Id: courseID})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject metadata naming another tenant", func(t *testing.T) {
+		conn, repo := setupServerWith(t, cfg, &config.AuthzConfig{}, new(mocks.MockAPIKeyRepository))
+		ctx := metadata.AppendToOutgoingContext(context.Background(),
+			"authorization", "Bearer "+signToken(t, secret, "school-a"),
+			"x-tenant-id", "school-b",
+		)
+
+		_, err := coursev1.NewCourseServiceClient(conn).GetCourse(ctx, &coursev1.GetCourseRequest{Id: courseID})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		repo.AssertNotCalled(t, "GetCourseByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject credentials bound to no tenant", func(t *testing.T) {
+		conn, repo := setupServerWith(t, cfg, &config.AuthzConfig{}, new(mocks.MockAPIKeyRepository))
+		ctx := metadata.AppendToOutgoingContext(context.Background(),
+			"authorization", "Bearer "+signToken(t, secret, ""),
+			"x-tenant-id", "school-b",
+		)
+
+		_, err := coursev1.NewCourseServiceClient(conn).GetCourse(ctx, &coursev1.GetCourseRequest{Id: courseID})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		repo.AssertNotCalled(t, "GetCourseByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should use the metadata when the token may act on any tenant", func(t *testing.T) {
+		conn, repo := setupServerWith(t, cfg, &config.AuthzConfig{}, new(mocks.MockAPIKeyRepository))
+		ctx := metadata.AppendToOutgoingContext(context.Background(),
+			"authorization", "Bearer "+signToken(t, secret, tenant.AnyTenant),
+			"x-tenant-id", "school-b",
+		)
+		repo.On("GetCourseByID", inTenant("school-b"), courseID).Return(course, nil)
+
+		_, err := coursev1.NewCourseServiceClient(conn).GetCourse(ctx, &coursev1.GetCourseRequest{Id: courseID})
+
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/marcelofabianov/fault"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+const errorDomain = "dojo-go"
+
+var grpcCodes = map[fault.Code]codes.Code{
+	fault.Invalid:         codes.InvalidArgument,
+	fault.NotFound:        codes.NotFound,
+	fault.Conflict:        codes.AlreadyExists,
+	fault.Unauthorized:    codes.Unauthenticated,
+	fault.Forbidden:       codes.PermissionDenied,
+	fault.DomainViolation: codes.FailedPrecondition,
+	fault.InfraError:      codes.Unavailable,
+	fault.Internal:        codes.Internal,
+	web.Timeout:           codes.DeadlineExceeded,
+	web.Unavailable:       codes.Unavailable,
+}
+
+func GRPCCode(code fault.Code) codes.Code {
+	if c, ok := grpcCodes[code]; ok {
+		return c
+	}
+	return codes.Internal
+}
+
+// toStatus converts service errors into a gRPC status carrying the fault code
+// and context as ErrorInfo and validation details as BadRequest violations.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, model.ErrCourseNotFound):
+		err = fault.New("course not found", fault.WithCode(fault.NotFound))
+	case errors.Is(err, model.ErrEmptyTitle), errors.Is(err, model.ErrEmptyDescription):
+		err = fault.Wrap(err, "Request validation failed", fault.WithCode(fault.Invalid))
+	}
+
+	resp := web.ToResponse(err)
+	st := status.New(GRPCCode(fault.Code(resp.Code)), resp.Message)
+
+	info := &errdetails.ErrorInfo{
+		Reason:   resp.Code,
+		Domain:   errorDomain,
+		Metadata: make(map[string]string, len(resp.Context)),
+	}
+	for k, v := range resp.Context {
+		info.Metadata[k] = fmt.Sprint(v)
+	}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range resp.Details {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       fmt.Sprint(detail.Context["field"]),
+			Description: detail.Message,
+		})
+	}
+
+	withDetails, detailsErr := st.WithDetails(info)
+	if detailsErr == nil {
+		st = withDetails
+	}
+	if len(violations) > 0 {
+		if withDetails, detailsErr := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); detailsErr == nil {
+			st = withDetails
+		}
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,211 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/marcelofabianov/fault"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/auth"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	coursev1 "github.com/marcelofabianov/dojo-go/pkg/pb/course/v1"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+const (
+	requestIDMetadataKey     = "x-request-id"
+	tenantMetadataKey        = "x-tenant-id"
+	apiKeyMetadataKey        = "x-api-key"
+	authorizationMetadataKey = "authorization"
+)
+
+func NewHealthServer() *health.Server {
+	return health.NewServer()
+}
+
+func NewServer(
+	cfg *config.ServerConfig,
+	logger *slog.Logger,
+	healthServer *health.Server,
+	courseServer coursev1.CourseServiceServer,
+	replicas *db.ReplicaSet,
+	verifier *auth.Verifier,
+	apiKeyService port.APIKeyServicePort,
+) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			loggerInterceptor(logger),
+			recoveryInterceptor(),
+			sessionInterceptor(replicas),
+			authInterceptor(cfg.Auth.Enabled, verifier, apiKeyService),
+			tenantInterceptor(cfg.Tenancy.Default),
+		),
+	)
+
+	coursev1.RegisterCourseServiceServer(srv, courseServer)
+	healthpb.RegisterHealthServer(srv, healthServer)
+	reflection.Register(srv)
+
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(coursev1.CourseService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	return srv
+}
+
+func Addr(cfg *config.ServerConfig) string {
+	return fmt.Sprintf("%s:%d", cfg.GRPC.Host, cfg.GRPC.Port)
+}
+
+// loggerInterceptor mirrors SlogLoggerMiddleware: it attaches a logger tagged
+// with the caller's x-request-id (or a new one) so handlers can use
+// web.GetLogger.
+func loggerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		requestID := incomingMetadata(ctx, requestIDMetadataKey)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+
+		ctxLogger := logger.With("request_id", requestID, "grpc_method", info.FullMethod)
+		ctx = context.WithValue(ctx, web.LoggerCtxKey, ctxLogger)
+		ctx = web.WithRequestID(ctx, requestID)
+
+		return handler(ctx, req)
+	}
+}
+
+// sessionInterceptor mirrors db.ReplicaSet.Middleware: each call reads from
+// the replicas until it writes.
+func sessionInterceptor(replicas *db.ReplicaSet) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(db.WithSession(ctx, replicas), req)
+	}
+}
+
+func recoveryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				web.GetLogger(ctx).Error("panic recovered in grpc handler", "panic", r, "stack", string(debug.Stack()))
+				err = toStatus(fault.NewInternalError(fmt.Errorf("panic: %v", r), nil))
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// authInterceptor mirrors the HTTP chain of APIKeyMiddleware, auth.Middleware
+// and authz.PrincipalMiddleware: calls carrying x-api-key metadata run as the
+// key, the others need a bearer token in the authorization metadata while
+// auth is enabled. Health checks stay open so probes need no credentials.
+func authInterceptor(enabled bool, verifier *auth.Verifier, apiKeyService port.APIKeyServicePort) grpc.UnaryServerInterceptor {
+	healthPrefix := "/" + healthpb.Health_ServiceDesc.ServiceName + "/"
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if strings.HasPrefix(info.FullMethod, healthPrefix) {
+			return handler(ctx, req)
+		}
+
+		logger := web.GetLogger(ctx)
+
+		if token := incomingMetadata(ctx, apiKeyMetadataKey); token != "" {
+			key, err := apiKeyService.AuthenticateAPIKey(ctx, token)
+			if err != nil {
+				logger.Warn("api key rejected", "error", err)
+				return nil, toStatus(err)
+			}
+
+			permissions := make([]authz.Permission, 0, len(key.Scopes))
+			for _, scope := range key.Scopes {
+				permissions = append(permissions, authz.Permission(scope))
+			}
+
+			ctx = authz.WithPrincipal(ctx, authz.Principal{ID: "apikey:" + key.ID, Permissions: permissions})
+			ctx = tenant.WithID(ctx, key.TenantID)
+			ctx = auth.MarkAuthenticated(ctx)
+			ctx = web.WithLoggerAttrs(ctx, "api_key_id", key.ID, "api_key_prefix", key.Prefix)
+
+			return handler(ctx, req)
+		}
+
+		if !enabled {
+			return handler(ctx, req)
+		}
+
+		token := bearerToken(incomingMetadata(ctx, authorizationMetadataKey))
+		if token == "" {
+			logger.Warn("missing bearer token")
+			return nil, toStatus(fault.New("missing bearer token", fault.WithCode(fault.Unauthorized)))
+		}
+
+		claims, err := verifier.Verify(ctx, token)
+		if err != nil {
+			logger.Warn("bearer token rejected", "error", err)
+			return nil, toStatus(err)
+		}
+
+		ctx = auth.WithClaims(ctx, claims)
+		ctx = authz.WithPrincipal(ctx, authz.Principal{ID: claims.Subject, Roles: claims.Roles})
+		ctx = web.WithLoggerAttrs(ctx, "subject", claims.Subject)
+
+		return handler(ctx, req)
+	}
+}
+
+func incomingMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func bearerToken(header string) string {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// tenantInterceptor mirrors tenant.Middleware: the tenant bound to the
+// credentials wins and the x-tenant-id metadata may only repeat it. Anonymous
+// calls and multi-tenant tokens use the metadata, then the default tenant.
+func tenantInterceptor(defaultID string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		requested := strings.ToLower(strings.TrimSpace(incomingMetadata(ctx, tenantMetadataKey)))
+
+		id, err := tenant.Resolve(ctx, requested, defaultID)
+		if err == nil && id == "" {
+			err = fault.New("missing tenant",
+				fault.WithCode(fault.Invalid),
+				fault.WithContext("metadata", tenantMetadataKey),
+			)
+		}
+		if err != nil {
+			web.GetLogger(ctx).Warn("tenant resolution failed", "error", err)
+			return nil, toStatus(err)
+		}
+
+		ctx = tenant.WithID(ctx, id)
+		ctx = web.WithLoggerAttrs(ctx, "tenant", id)
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+// lastUsedResolution limits last-used tracking to one write per key per
+// minute instead of one per request.
+const lastUsedResolution = time.Minute
+
+type APIKeyService struct {
+	repo       port.APIKeyRepositoryPort
+	transactor port.TransactorPort
+	policy     *authz.Policy
+}
+
+func NewAPIKeyService(repo port.APIKeyRepositoryPort, transactor port.TransactorPort, policy *authz.Policy) port.APIKeyServicePort {
+	return &APIKeyService{
+		repo:       repo,
+		transactor: transactor,
+		policy:     policy,
+	}
+}
+
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, input model.NewAPIKeyInput) (*model.APIKey, string, error) {
+	if err := s.policy.Authorize(ctx, authz.APIKeyManage); err != nil {
+		return nil, "", err
+	}
+
+	for _, scope := range input.Scopes {
+		if !authz.IsPermission(scope) {
+			return nil, "", fault.New("unknown api key scope",
+				fault.WithCode(fault.Invalid),
+				fault.WithContext("scope", scope),
+			)
+		}
+	}
+
+	if err := s.authorizeScopes(ctx, input.Scopes); err != nil {
+		return nil, "", err
+	}
+
+	key, token, err := model.NewAPIKey(input)
+	if err != nil {
+		return nil, "", fault.Wrap(err, "api key validation failed", fault.WithCode(fault.Invalid))
+	}
+
+	if err := s.repo.CreateAPIKey(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	return key, token, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	if err := s.policy.Authorize(ctx, authz.APIKeyManage); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListAPIKeys(ctx)
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := s.policy.Authorize(ctx, authz.APIKeyManage); err != nil {
+		return err
+	}
+
+	return s.repo.RevokeAPIKey(ctx, id, time.Now())
+}
+
+// RotateAPIKey issues a new key with the same name, scopes and expiry and
+// revokes the old one in the same transaction.
+func (s *APIKeyService) RotateAPIKey(ctx context.Context, id string) (*model.APIKey, string, error) {
+	if err := s.policy.Authorize(ctx, authz.APIKeyManage); err != nil {
+		return nil, "", err
+	}
+
+	var (
+		key   *model.APIKey
+		token string
+	)
+
+	err := s.transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+		old, err := s.repo.GetAPIKeyByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if old.IsRevoked() {
+			return fault.New("api key is already revoked",
+				fault.WithCode(fault.DomainViolation),
+				fault.WithContext("id", id),
+			)
+		}
+
+		if err := s.authorizeScopes(ctx, old.Scopes); err != nil {
+			return err
+		}
+
+		key, token, err = model.NewAPIKey(model.NewAPIKeyInput{
+			Name:      old.Name,
+			Scopes:    old.Scopes,
+			ExpiresAt: old.ExpiresAt,
+		})
+		if err != nil {
+			return fault.Wrap(err, "api key cannot be rotated", fault.WithCode(fault.DomainViolation))
+		}
+		key.RotatedFrom = &old.ID
+
+		if err := s.repo.CreateAPIKey(ctx, key); err != nil {
+			return err
+		}
+
+		return s.repo.RevokeAPIKey(ctx, old.ID, time.Now())
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, token, nil
+}
+
+// authorizeScopes keeps callers from issuing keys stronger than themselves:
+// each scope must be a permission the caller holds.
+func (s *APIKeyService) authorizeScopes(ctx context.Context, scopes []string) error {
+	for _, scope := range scopes {
+		if err := s.policy.Authorize(ctx, authz.Permission(scope)); err != nil {
+			return fault.Wrap(err,
+				"api key scope exceeds the caller's permissions",
+				fault.WithCode(fault.Forbidden),
+				fault.WithContext("scope", scope),
+			)
+		}
+	}
+	return nil
+}
+
+// AuthenticateAPIKey resolves a token to an active key. Every failure is
+// reported as Unauthorized so callers cannot probe which keys exist.
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, token string) (*model.APIKey, error) {
+	invalid := fault.New("invalid api key", fault.WithCode(fault.Unauthorized))
+
+	prefix, secret, err := model.ParseAPIKeyToken(token)
+	if err != nil {
+		return nil, invalid
+	}
+
+	key, err := s.repo.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, model.ErrAPIKeyNotFound) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !key.Matches(secret) || key.IsRevoked() || key.IsExpired(now) {
+		return nil, invalid
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		// Last-used tracking is best effort and never fails authentication.
+		// It runs apart from the request, in the key's tenant, so that this
+		// bookkeeping write does not pin the request's reads to the primary.
+		touchCtx := tenant.WithID(context.Background(), key.TenantID)
+		if err := s.repo.TouchAPIKey(touchCtx, key.ID, now); err == nil {
+			key.LastUsedAt = &now
+		}
+	}
+
+	return key, nil
+}
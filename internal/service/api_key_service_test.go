@@ -0,0 +1,216 @@
+//go:build unit
+
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/mocks"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	service "github.com/marcelofabianov/dojo-go/internal/service"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+type requestValue struct{}
+
+func setupAPIKeys(authzCfg *config.AuthzConfig) (*mocks.MockAPIKeyRepository, *mocks.FakeTransactor, port.APIKeyServicePort) {
+	repo := new(mocks.MockAPIKeyRepository)
+	tx := &mocks.FakeTransactor{}
+	return repo, tx, service.NewAPIKeyService(repo, tx, authz.NewPolicy(authzCfg))
+}
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	t.Run("should create a key and return the token once", func(t *testing.T) {
+		repo, _, svc := setupAPIKeys(&config.AuthzConfig{})
+		repo.On("CreateAPIKey", mock.Anything, mock.AnythingOfType("*model.APIKey")).Return(nil)
+
+		key, token, err := svc.CreateAPIKey(context.Background(), model.NewAPIKeyInput{
+			Name:   "sync-job",
+			Scopes: []string{"course:read", "course:create"},
+		})
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(token, "dojo_"+key.Prefix+"_"))
+		assert.NotContains(t, key.KeyHash, strings.TrimPrefix(token, "dojo_"+key.Prefix+"_"))
+		assert.Equal(t, model.Scopes{"course:read", "course:create"}, key.Scopes)
+	})
+
+	t.Run("should reject unknown scopes", func(t *testing.T) {
+		_, _, svc := setupAPIKeys(&config.AuthzConfig{})
+
+		_, _, err := svc.CreateAPIKey(context.Background(), model.NewAPIKeyInput{Name: "job", Scopes: []string{"course:drop"}})
+
+		assert.True(t, fault.IsCode(err, fault.Invalid))
+	})
+
+	t.Run("should require the apikey:manage permission", func(t *testing.T) {
+		_, _, svc := setupAPIKeys(&config.AuthzConfig{Enabled: true, Roles: map[string][]string{"editor": {"course:update"}}})
+		ctx := authz.WithPrincipal(context.Background(), authz.Principal{ID: "user-1", Roles: []string{"editor"}})
+
+		_, _, err := svc.CreateAPIKey(ctx, model.NewAPIKeyInput{Name: "job", Scopes: []string{"course:read"}})
+
+		assert.True(t, fault.IsCode(err, fault.Forbidden))
+	})
+
+	t.Run("should not grant scopes the caller does not hold", func(t *testing.T) {
+		repo, _, svc := setupAPIKeys(&config.AuthzConfig{Enabled: true, Roles: map[string][]string{"keys": {"apikey:manage", "course:read"}}})
+		ctx := authz.WithPrincipal(context.Background(), authz.Principal{ID: "user-1", Roles: []string{"keys"}})
+
+		for _, scope := range []string{"course:delete", "audit:read"} {
+			_, _, err := svc.CreateAPIKey(ctx, model.NewAPIKeyInput{Name: "job", Scopes: []string{"course:read", scope}})
+
+			assert.True(t, fault.IsCode(err, fault.Forbidden), scope)
+		}
+		repo.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should grant scopes the caller holds", func(t *testing.T) {
+		repo, _, svc := setupAPIKeys(&config.AuthzConfig{Enabled: true, Roles: map[string][]string{"keys": {"apikey:manage", "course:read"}}})
+		ctx := authz.WithPrincipal(context.Background(), authz.Principal{ID: "user-1", Roles: []string{"keys"}})
+		repo.On("CreateAPIKey", mock.Anything, mock.AnythingOfType("*model.APIKey")).Return(nil)
+
+		_, _, err := svc.CreateAPIKey(ctx, model.NewAPIKeyInput{Name: "job", Scopes: []string{"course:read"}})
+
+		require.NoError(t, err)
+	})
+}
+
+func TestAPIKeyService_AuthenticateAPIKey(t *testing.T) {
+	newKey := func(t *testing.T) (*model.APIKey, string) {
+		key, token, err := model.NewAPIKey(model.NewAPIKeyInput{Name: "job", Scopes: []string{"course:read"}})
+		require.NoError(t, err)
+		return key, token
+	}
+
+	t.Run("should resolve a valid token and track its last use", func(t *testing.T) {
+		repo, _, svc := setupAPIKeys(&config.AuthzConfig{})
+		key, token := newKey(t)
+		repo.On("GetAPIKeyByPrefix", mock.Anything, key.Prefix).Return(key, nil)
+		repo.On("TouchAPIKey", mock.Anything, key.ID, mock.Anything).Return(nil).Once()
+
+		got, err := svc.AuthenticateAPIKey(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, key.ID, got.ID)
+
+		_, err = svc.AuthenticateAPIKey(context.Background(), token)
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should track the last use apart from the request, in the key's tenant", func(t *testing.T) {
+		repo, _, svc := setupAPIKeys(&config.AuthzConfig{})
+		key, token := newKey(t)
+		key.TenantID = "school-a"
+		repo.On("GetAPIKeyByPrefix", mock.Anything, key.Prefix).Return(key, nil)
+		detached := mock.MatchedBy(func(ctx context.Context) bool {
+			got, _ := tenant.FromContext(ctx)
+			return got == "school-a" && ctx.Err() == nil && ctx.Value(requestValue{}) == nil
+		})
+		repo.On("TouchAPIKey", detached, key.ID, mock.Anything).Return(nil).Once()
+
+		// The request carries its own values, such as its read session, and
+		// may end before the write does.
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), requestValue{}, true))
+		cancel()
+
+		_, err := svc.AuthenticateAPIKey(ctx, token)
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		mutate func(key *model.APIKey, token string) string
+	}{
+		{"malformed token", func(_ *model.APIKey, _ string) string { return "not-a-key" }},
+		{"wrong secret", func(_ *model.APIKey, token string) string { return token + "x" }},
+		{"revoked key", func(key *model.APIKey, token string) string { key.RevokedAt = &past; return token }},
+		{"expired key", func(key *model.APIKey, token string) string { key.ExpiresAt = &past; return token }},
+	}
+
+	for _, tt := range tests {
+		t.Run("should reject "+tt.name, func(t *testing.T) {
+			repo, _, svc := setupAPIKeys(&config.AuthzConfig{})
+			key, token := newKey(t)
+			token = tt.mutate(key, token)
+			repo.On("GetAPIKeyByPrefix", mock.Anything, key.Prefix).Return(key, nil).Maybe()
+
+			_, err := svc.AuthenticateAPIKey(context.Background(), token)
+
+			assert.True(t, fault.IsCode(err, fault.Unauthorized))
+			repo.AssertNotCalled(t, "TouchAPIKey", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("should reject unknown prefixes", func(t *testing.T) {
+		repo, _, svc := setupAPIKeys(&config.AuthzConfig{})
+		repo.On("GetAPIKeyByPrefix", mock.Anything, "000000000000").Return(nil, model.ErrAPIKeyNotFound)
+
+		_, err := svc.AuthenticateAPIKey(context.Background(), "dojo_000000000000_secret")
+
+		assert.True(t, fault.IsCode(err, fault.Unauthorized))
+	})
+}
+
+func TestAPIKeyService_RotateAPIKey(t *testing.T) {
+	t.Run("should issue a new key and revoke the old one in a transaction", func(t *testing.T) {
+		repo, tx, svc := setupAPIKeys(&config.AuthzConfig{})
+		old, _, err := model.NewAPIKey(model.NewAPIKeyInput{Name: "job", Scopes: []string{"course:read"}})
+		require.NoError(t, err)
+
+		repo.On("GetAPIKeyByID", mock.Anything, old.ID).Return(old, nil)
+		repo.On("CreateAPIKey", mock.Anything, mock.AnythingOfType("*model.APIKey")).Return(nil)
+		repo.On("RevokeAPIKey", mock.Anything, old.ID, mock.Anything).Return(nil)
+
+		key, token, err := svc.RotateAPIKey(context.Background(), old.ID)
+
+		require.NoError(t, err)
+		assert.True(t, tx.Committed)
+		assert.NotEqual(t, old.Prefix, key.Prefix)
+		assert.Equal(t, old.ID, *key.RotatedFrom)
+		assert.Equal(t, old.Scopes, key.Scopes)
+		assert.NotEmpty(t, token)
+	})
+
+	t.Run("should not rotate a revoked key", func(t *testing.T) {
+		repo, tx, svc := setupAPIKeys(&config.AuthzConfig{})
+		old, _, err := model.NewAPIKey(model.NewAPIKeyInput{Name: "job", Scopes: []string{"course:read"}})
+		require.NoError(t, err)
+		revokedAt := time.Now()
+		old.RevokedAt = &revokedAt
+		repo.On("GetAPIKeyByID", mock.Anything, old.ID).Return(old, nil)
+
+		_, _, err = svc.RotateAPIKey(context.Background(), old.ID)
+
+		assert.True(t, fault.IsCode(err, fault.DomainViolation))
+		assert.True(t, tx.RolledBack)
+		repo.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not rotate a key with scopes the caller does not hold", func(t *testing.T) {
+		repo, tx, svc := setupAPIKeys(&config.AuthzConfig{Enabled: true, Roles: map[string][]string{"keys": {"apikey:manage", "course:read"}}})
+		ctx := authz.WithPrincipal(context.Background(), authz.Principal{ID: "user-1", Roles: []string{"keys"}})
+		old, _, err := model.NewAPIKey(model.NewAPIKeyInput{Name: "job", Scopes: []string{"course:read", "course:delete"}})
+		require.NoError(t, err)
+		repo.On("GetAPIKeyByID", mock.Anything, old.ID).Return(old, nil)
+
+		_, _, err = svc.RotateAPIKey(ctx, old.ID)
+
+		assert.True(t, fault.IsCode(err, fault.Forbidden))
+		assert.True(t, tx.RolledBack)
+		repo.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+type AuditService struct {
+	repo   port.AuditRepositoryPort
+	policy *authz.Policy
+}
+
+func NewAuditService(repo port.AuditRepositoryPort, policy *authz.Policy) port.AuditServicePort {
+	return &AuditService{repo: repo, policy: policy}
+}
+
+// ListAuditEntries returns one page of entries, newest first, and the cursor
+// of the next page, empty on the last one.
+func (s *AuditService) ListAuditEntries(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, string, error) {
+	if err := s.policy.Authorize(ctx, authz.AuditRead); err != nil {
+		return nil, "", err
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditPageSize
+	}
+	limit = min(limit, maxAuditPageSize)
+
+	// Fetch one extra entry to know whether there is a next page.
+	filter.Limit = limit + 1
+	entries, err := s.repo.ListAuditEntries(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(entries) <= limit {
+		return entries, "", nil
+	}
+
+	entries = entries[:limit]
+	return entries, entries[limit-1].ID, nil
+}
+
+// ExportAuditEntries streams every entry matching filter to fn, page by page,
+// so exports of any size run in constant memory.
+func (s *AuditService) ExportAuditEntries(ctx context.Context, filter model.AuditFilter, fn func(*model.AuditEntry) error) error {
+	if err := s.policy.Authorize(ctx, authz.AuditRead); err != nil {
+		return err
+	}
+
+	filter.Limit = maxAuditPageSize
+	for {
+		entries, err := s.repo.ListAuditEntries(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+
+		if len(entries) < filter.Limit {
+			return nil
+		}
+		filter.Cursor = entries[len(entries)-1].ID
+	}
+}
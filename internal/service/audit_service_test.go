@@ -0,0 +1,93 @@
+//go:build unit
+
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/mocks"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	service "github.com/marcelofabianov/dojo-go/internal/service"
+)
+
+func auditEntries(n int) []*model.AuditEntry {
+	entries := make([]*model.AuditEntry, n)
+	for i := range entries {
+		entries[i] = &model.AuditEntry{ID: fmt.Sprintf("entry-%03d", n-i)}
+	}
+	return entries
+}
+
+func TestAuditService_ListAuditEntries(t *testing.T) {
+	t.Run("should return a cursor when there are more entries", func(t *testing.T) {
+		repo := new(mocks.MockAuditRepository)
+		repo.On("ListAuditEntries", mock.Anything, mock.MatchedBy(func(f model.AuditFilter) bool {
+			return f.Limit == 3 && f.Actor == "user-1"
+		})).Return(auditEntries(3), nil)
+		svc := service.NewAuditService(repo, authz.NewPolicy(&config.AuthzConfig{}))
+
+		entries, next, err := svc.ListAuditEntries(context.Background(), model.AuditFilter{Actor: "user-1", Limit: 2})
+
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+		assert.Equal(t, entries[1].ID, next)
+	})
+
+	t.Run("should not return a cursor on the last page", func(t *testing.T) {
+		repo := new(mocks.MockAuditRepository)
+		repo.On("ListAuditEntries", mock.Anything, mock.Anything).Return(auditEntries(1), nil)
+		svc := service.NewAuditService(repo, authz.NewPolicy(&config.AuthzConfig{}))
+
+		entries, next, err := svc.ListAuditEntries(context.Background(), model.AuditFilter{})
+
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Empty(t, next)
+	})
+
+	t.Run("should require the audit:read permission", func(t *testing.T) {
+		repo := new(mocks.MockAuditRepository)
+		svc := service.NewAuditService(repo, authz.NewPolicy(&config.AuthzConfig{
+			Enabled: true,
+			Roles:   map[string][]string{"editor": {"course:read", "course:update"}},
+		}))
+
+		_, _, err := svc.ListAuditEntries(withRoles("editor"), model.AuditFilter{})
+
+		assert.True(t, fault.IsCode(err, fault.Forbidden))
+		repo.AssertNotCalled(t, "ListAuditEntries", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuditService_ExportAuditEntries(t *testing.T) {
+	t.Run("should page through every entry", func(t *testing.T) {
+		firstPage := auditEntries(500)
+		repo := new(mocks.MockAuditRepository)
+		repo.On("ListAuditEntries", mock.Anything, mock.MatchedBy(func(f model.AuditFilter) bool {
+			return f.Cursor == ""
+		})).Return(firstPage, nil).Once()
+		repo.On("ListAuditEntries", mock.Anything, mock.MatchedBy(func(f model.AuditFilter) bool {
+			return f.Cursor == firstPage[499].ID
+		})).Return(auditEntries(2), nil).Once()
+		svc := service.NewAuditService(repo, authz.NewPolicy(&config.AuthzConfig{}))
+
+		exported := 0
+		err := svc.ExportAuditEntries(context.Background(), model.AuditFilter{}, func(*model.AuditEntry) error {
+			exported++
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 502, exported)
+		repo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+)
+
+type CourseBatchService struct {
+	courseService port.CourseServicePort
+	transactor    port.TransactorPort
+}
+
+func NewCourseBatchService(courseService port.CourseServicePort, transactor port.TransactorPort) port.CourseBatchServicePort {
+	return &CourseBatchService{
+		courseService: courseService,
+		transactor:    transactor,
+	}
+}
+
+// ExecuteBatch applies ops in order and returns one result per operation.
+// In atomic mode the batch stops at the first failure and every other
+// operation is reported as not applied.
+func (s *CourseBatchService) ExecuteBatch(ctx context.Context, mode model.BatchMode, ops []model.BatchOperation) []model.BatchResult {
+	results := make([]model.BatchResult, len(ops))
+
+	if mode != model.BatchAtomic {
+		for i, op := range ops {
+			results[i] = s.apply(ctx, op)
+		}
+		return results
+	}
+
+	failed := -1
+	err := s.transactor.WithinTx(ctx, serializable, func(ctx context.Context) error {
+		// The transactor replays fn on serialization failures, so nothing may
+		// be left over from a previous attempt.
+		failed = -1
+		clear(results)
+
+		for i, op := range ops {
+			results[i] = s.apply(ctx, op)
+			if results[i].Err != nil {
+				failed = i
+				return results[i].Err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		return results
+	}
+
+	for i := range results {
+		switch {
+		case i == failed:
+		case failed == -1:
+			results[i] = model.BatchResult{Err: err}
+		case i < failed:
+			results[i] = model.BatchResult{Err: fault.New("operation rolled back because another operation in the batch failed",
+				fault.WithCode(fault.Conflict),
+				fault.WithContext("failed_index", failed),
+			)}
+		default:
+			results[i] = model.BatchResult{Err: fault.New("operation not executed because another operation in the batch failed",
+				fault.WithCode(fault.Conflict),
+				fault.WithContext("failed_index", failed),
+			)}
+		}
+	}
+
+	return results
+}
+
+func (s *CourseBatchService) apply(ctx context.Context, op model.BatchOperation) model.BatchResult {
+	switch op.Type {
+	case model.BatchCreate:
+		course, err := s.courseService.CreateCourse(ctx, model.NewCourseInput{
+			Title:       op.Title,
+			Description: op.Description,
+		})
+		return model.BatchResult{Course: course, Err: err}
+	case model.BatchUpdate:
+		course, err := s.courseService.UpdateCourse(ctx, op.ID, model.UpdateCourseInput{
+			Title:       op.Title,
+			Description: op.Description,
+		})
+		return model.BatchResult{Course: course, Err: err}
+	case model.BatchDelete:
+		return model.BatchResult{Err: s.courseService.DeleteCourseByID(ctx, op.ID)}
+	default:
+		return model.BatchResult{Err: fault.New("unsupported batch operation",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("op", op.Type),
+		)}
+	}
+}
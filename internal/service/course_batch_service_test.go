@@ -0,0 +1,126 @@
+//go:build unit
+
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/mocks"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	service "github.com/marcelofabianov/dojo-go/internal/service"
+)
+
+// retryingTransactor replays fn once after a failed attempt, like the SQL
+// transactor on a serialization failure, and then fails to commit.
+type retryingTransactor struct {
+	commitErr error
+}
+
+func (t retryingTransactor) WithinTx(ctx context.Context, _ *sql.TxOptions, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+	}
+	return t.commitErr
+}
+
+func (retryingTransactor) AfterCommit(_ context.Context, fn func()) { fn() }
+
+func TestCourseBatchService_ExecuteBatch(t *testing.T) {
+	existingID := "0199a000-0000-7000-8000-00000000000a"
+	missingID := "0199a000-0000-7000-8000-00000000000b"
+
+	ops := []model.BatchOperation{
+		{Type: model.BatchCreate, Title: "Go", Description: "Go course"},
+		{Type: model.BatchDelete, ID: missingID},
+		{Type: model.BatchDelete, ID: existingID},
+	}
+
+	setupBatch := func() (*mocks.MockCourseRepository, *mocks.FakeTransactor, *mocks.FakeMetrics, *service.CourseBatchService) {
+		repo := new(mocks.MockCourseRepository)
+		repo.On("CreateCourse", mock.Anything, mock.AnythingOfType("*model.Course")).Return(nil)
+		repo.On("GetCourseByID", mock.Anything, missingID).Return(nil, model.ErrCourseNotFound)
+		repo.On("GetCourseByID", mock.Anything, existingID).Return(&model.Course{ID: existingID, Title: "Rust", Description: "Rust course"}, nil)
+		repo.On("DeleteCourseByID", mock.Anything, existingID).Return(nil)
+
+		// The course service gets its own transactor so that only the
+		// transaction opened by the batch itself is observed.
+		metrics := &mocks.FakeMetrics{}
+		courseService := service.NewCourseService(repo, mocks.NewCourseRevisionRepositoryStub(), mocks.NewAuditRepositoryStub(), &mocks.FakeTransactor{}, metrics, authz.NewPolicy(&config.AuthzConfig{}))
+
+		tx := &mocks.FakeTransactor{}
+		svc := service.NewCourseBatchService(courseService, tx).(*service.CourseBatchService)
+		return repo, tx, metrics, svc
+	}
+
+	t.Run("should apply every operation independently in best effort mode", func(t *testing.T) {
+		repo, tx, metrics, svc := setupBatch()
+
+		results := svc.ExecuteBatch(context.Background(), model.BatchBestEffort, ops)
+
+		require.Len(t, results, 3)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, "Go", results[0].Course.Title)
+		assert.ErrorIs(t, results[1].Err, model.ErrCourseNotFound)
+		assert.NoError(t, results[2].Err)
+		assert.False(t, tx.Committed || tx.RolledBack)
+		repo.AssertNumberOfCalls(t, "DeleteCourseByID", 1)
+		assert.Equal(t, []string{"course.created", "course.deleted"}, metrics.Events)
+	})
+
+	t.Run("should roll back and stop at the first failure in atomic mode", func(t *testing.T) {
+		repo, tx, metrics, svc := setupBatch()
+
+		results := svc.ExecuteBatch(context.Background(), model.BatchAtomic, ops)
+
+		require.Len(t, results, 3)
+		assert.True(t, tx.RolledBack)
+		assert.True(t, fault.IsCode(results[0].Err, fault.Conflict))
+		assert.Nil(t, results[0].Course)
+		assert.ErrorIs(t, results[1].Err, model.ErrCourseNotFound)
+		assert.True(t, fault.IsCode(results[2].Err, fault.Conflict))
+		repo.AssertNotCalled(t, "DeleteCourseByID", mock.Anything, existingID)
+		assert.Empty(t, metrics.Events, "the create was rolled back with the batch")
+	})
+
+	t.Run("should commit when every operation succeeds in atomic mode", func(t *testing.T) {
+		_, tx, metrics, svc := setupBatch()
+
+		results := svc.ExecuteBatch(context.Background(), model.BatchAtomic, []model.BatchOperation{ops[0], ops[2]})
+
+		assert.True(t, tx.Committed)
+		assert.Equal(t, sql.LevelSerializable, tx.Options.Isolation)
+		assert.NoError(t, results[0].Err)
+		assert.NoError(t, results[1].Err)
+		assert.Equal(t, []string{"course.created", "course.deleted"}, metrics.Events)
+	})
+
+	t.Run("should not report a failure from a previous attempt", func(t *testing.T) {
+		repo := new(mocks.MockCourseRepository)
+		repo.On("CreateCourse", mock.Anything, mock.AnythingOfType("*model.Course")).Return(nil)
+		repo.On("GetCourseByID", mock.Anything, existingID).Return(nil, model.ErrCourseNotFound).Once()
+		repo.On("GetCourseByID", mock.Anything, existingID).Return(&model.Course{ID: existingID, Title: "Rust", Description: "Rust course"}, nil)
+		repo.On("DeleteCourseByID", mock.Anything, existingID).Return(nil)
+		courseService := service.NewCourseService(repo, mocks.NewCourseRevisionRepositoryStub(), mocks.NewAuditRepositoryStub(), &mocks.FakeTransactor{}, &mocks.FakeMetrics{}, authz.NewPolicy(&config.AuthzConfig{}))
+		commitErr := fault.New("commit failed", fault.WithCode(fault.Internal))
+		svc := service.NewCourseBatchService(courseService, retryingTransactor{commitErr: commitErr})
+
+		results := svc.ExecuteBatch(context.Background(), model.BatchAtomic, []model.BatchOperation{ops[0], ops[2]})
+
+		require.Len(t, results, 2)
+		for _, result := range results {
+			assert.ErrorIs(t, result.Err, commitErr)
+			assert.Nil(t, result.Course)
+		}
+	})
+}
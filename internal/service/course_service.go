@@ -2,28 +2,76 @@ package service
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/marcelofabianov/fault"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/marcelofabianov/dojo-go/internal/authz"
 	"github.com/marcelofabianov/dojo-go/internal/model"
 	"github.com/marcelofabianov/dojo-go/internal/port"
+	"github.com/marcelofabianov/dojo-go/pkg/telemetry"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
 )
 
+var tracer = otel.Tracer("github.com/marcelofabianov/dojo-go/internal/service")
+
+// serializable is used by units of work that write based on what they read
+// from several rows, so that a concurrent change aborts them with a
+// serialization failure, which the transactor retries, instead of being lost.
+var serializable = &sql.TxOptions{Isolation: sql.LevelSerializable}
+
 type CourseService struct {
-	repo port.CourseRepositoryPort
+	repo       port.CourseRepositoryPort
+	revisions  port.CourseRevisionRepositoryPort
+	audit      port.AuditRepositoryPort
+	transactor port.TransactorPort
+	metrics    port.MetricsPort
+	policy     *authz.Policy
 }
 
-func NewCourseService(repo port.CourseRepositoryPort) port.CourseServicePort {
-	return &CourseService{repo: repo}
+func NewCourseService(
+	repo port.CourseRepositoryPort,
+	revisions port.CourseRevisionRepositoryPort,
+	audit port.AuditRepositoryPort,
+	transactor port.TransactorPort,
+	metrics port.MetricsPort,
+	policy *authz.Policy,
+) port.CourseServicePort {
+	return &CourseService{
+		repo:       repo,
+		revisions:  revisions,
+		audit:      audit,
+		transactor: transactor,
+		metrics:    metrics,
+		policy:     policy,
+	}
 }
 
-func (c *CourseService) CreateCourse(ctx context.Context, input model.NewCourseInput) (*model.Course, error) {
+func (c *CourseService) CreateCourse(ctx context.Context, input model.NewCourseInput) (_ *model.Course, err error) {
+	ctx, span := tracer.Start(ctx, "CourseService.CreateCourse")
+	defer func() { telemetry.End(span, err) }()
+
+	if err := c.policy.Authorize(ctx, authz.CourseCreate); err != nil {
+		return nil, err
+	}
+
 	newCourse, err := model.NewCourse(input)
 	if err != nil {
 		return nil, err
 	}
 
-	err = c.repo.CreateCourse(ctx, newCourse)
+	err = c.transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+		if err := c.repo.CreateCourse(ctx, newCourse); err != nil {
+			return err
+		}
+		if _, err := c.revise(ctx, newCourse, nil); err != nil {
+			return err
+		}
+		return c.record(ctx, model.AuditCourseCreated, nil, newCourse)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -31,27 +79,242 @@ func (c *CourseService) CreateCourse(ctx context.Context, input model.NewCourseI
 	return newCourse, nil
 }
 
-func (c *CourseService) GetCourseByID(ctx context.Context, id string) (*model.Course, error) {
+func (c *CourseService) GetCourseByID(ctx context.Context, id string) (_ *model.Course, err error) {
+	ctx, span := tracer.Start(ctx, "CourseService.GetCourseByID", trace.WithAttributes(attribute.String("course.id", id)))
+	defer func() { telemetry.End(span, err) }()
+
+	if err := c.policy.Authorize(ctx, authz.CourseRead); err != nil {
+		return nil, err
+	}
+
 	return c.repo.GetCourseByID(ctx, id)
 }
 
-func (c *CourseService) DeleteCourseByID(ctx context.Context, id string) error {
-	return c.repo.DeleteCourseByID(ctx, id)
+// GetCoursesByIDs loads several courses at once, for callers that batch
+// lookups. Unknown ids are left out of the result.
+func (c *CourseService) GetCoursesByIDs(ctx context.Context, ids []string) (_ []*model.Course, err error) {
+	ctx, span := tracer.Start(ctx, "CourseService.GetCoursesByIDs", trace.WithAttributes(attribute.Int("course.count", len(ids))))
+	defer func() { telemetry.End(span, err) }()
+
+	if err := c.policy.Authorize(ctx, authz.CourseRead); err != nil {
+		return nil, err
+	}
+
+	return c.repo.GetCoursesByIDs(ctx, ids)
 }
 
-func (c *CourseService) UpdateCourse(ctx context.Context, id string, input model.UpdateCourseInput) (*model.Course, error) {
-	course, err := c.repo.GetCourseByID(ctx, id)
-	if err != nil {
+func (c *CourseService) ListCourses(ctx context.Context, limit int) (_ []*model.Course, err error) {
+	ctx, span := tracer.Start(ctx, "CourseService.ListCourses")
+	defer func() { telemetry.End(span, err) }()
+
+	if err := c.policy.Authorize(ctx, authz.CourseRead); err != nil {
 		return nil, err
 	}
 
-	if err := course.Update(input); err != nil {
-		return nil, fault.Wrap(err, "update validation failed", fault.WithCode(fault.Invalid))
+	if limit <= 0 {
+		return nil, fault.New("limit must be a positive number",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("limit", limit),
+		)
+	}
+
+	return c.repo.ListCourses(ctx, limit)
+}
+
+func (c *CourseService) DeleteCourseByID(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "CourseService.DeleteCourseByID", trace.WithAttributes(attribute.String("course.id", id)))
+	defer func() { telemetry.End(span, err) }()
+
+	if err := c.policy.Authorize(ctx, authz.CourseDelete); err != nil {
+		return err
+	}
+
+	err = c.transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+		course, err := c.repo.GetCourseByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := c.repo.DeleteCourseByID(ctx, id); err != nil {
+			return err
+		}
+
+		return c.record(ctx, model.AuditCourseDeleted, course, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CourseService) UpdateCourse(ctx context.Context, id string, input model.UpdateCourseInput) (_ *model.Course, err error) {
+	ctx, span := tracer.Start(ctx, "CourseService.UpdateCourse", trace.WithAttributes(attribute.String("course.id", id)))
+	defer func() { telemetry.End(span, err) }()
+
+	if err := c.policy.Authorize(ctx, authz.CourseUpdate); err != nil {
+		return nil, err
 	}
 
-	if err := c.repo.UpdateCourse(ctx, course); err != nil {
+	var course *model.Course
+	err = c.transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+		var err error
+		course, err = c.repo.GetCourseByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		before := *course
+
+		if err := course.Update(input); err != nil {
+			return fault.Wrap(err, "update validation failed", fault.WithCode(fault.Invalid))
+		}
+
+		if err := c.repo.UpdateCourse(ctx, course); err != nil {
+			return err
+		}
+
+		if _, err := c.revise(ctx, course, nil); err != nil {
+			return err
+		}
+
+		return c.record(ctx, model.AuditCourseUpdated, &before, course)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return course, nil
 }
+
+// ListCourseRevisions returns every revision of a course, newest first.
+func (c *CourseService) ListCourseRevisions(ctx context.Context, id string) (_ []*model.CourseRevision, err error) {
+	ctx, span := tracer.Start(ctx, "CourseService.ListCourseRevisions", trace.WithAttributes(attribute.String("course.id", id)))
+	defer func() { telemetry.End(span, err) }()
+
+	if err := c.policy.Authorize(ctx, authz.CourseRead); err != nil {
+		return nil, err
+	}
+
+	revisions, err := c.revisions.ListCourseRevisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every course has at least the revision written when it was created.
+	if len(revisions) == 0 {
+		return nil, model.ErrCourseNotFound
+	}
+
+	return revisions, nil
+}
+
+func (c *CourseService) GetCourseRevision(ctx context.Context, id string, revision int) (_ *model.CourseRevision, err error) {
+	ctx, span := tracer.Start(ctx, "CourseService.GetCourseRevision", trace.WithAttributes(attribute.String("course.id", id)))
+	defer func() { telemetry.End(span, err) }()
+
+	if err := c.policy.Authorize(ctx, authz.CourseRead); err != nil {
+		return nil, err
+	}
+
+	return c.revisions.GetCourseRevision(ctx, id, revision)
+}
+
+func (c *CourseService) DiffCourseRevisions(ctx context.Context, id string, from, to int) (_ model.FieldChanges, err error) {
+	ctx, span := tracer.Start(ctx, "CourseService.DiffCourseRevisions", trace.WithAttributes(attribute.String("course.id", id)))
+	defer func() { telemetry.End(span, err) }()
+
+	if err := c.policy.Authorize(ctx, authz.CourseRead); err != nil {
+		return nil, err
+	}
+
+	fromRevision, err := c.revisions.GetCourseRevision(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision, err := c.revisions.GetCourseRevision(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.DiffRevisions(fromRevision, toRevision), nil
+}
+
+// RollbackCourse restores the content of an older revision. History is never
+// rewritten: the restored content becomes a new revision.
+func (c *CourseService) RollbackCourse(ctx context.Context, id string, revision int) (_ *model.CourseRevision, err error) {
+	ctx, span := tracer.Start(ctx, "CourseService.RollbackCourse", trace.WithAttributes(attribute.String("course.id", id)))
+	defer func() { telemetry.End(span, err) }()
+
+	if err := c.policy.Authorize(ctx, authz.CourseUpdate); err != nil {
+		return nil, err
+	}
+
+	var restored *model.CourseRevision
+	err = c.transactor.WithinTx(ctx, serializable, func(ctx context.Context) error {
+		target, err := c.revisions.GetCourseRevision(ctx, id, revision)
+		if err != nil {
+			return err
+		}
+
+		course, err := c.repo.GetCourseByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		before := *course
+
+		if err := course.Update(model.UpdateCourseInput{Title: target.Title, Description: target.Description}); err != nil {
+			return fault.Wrap(err, "revision cannot be restored", fault.WithCode(fault.DomainViolation))
+		}
+
+		if err := c.repo.UpdateCourse(ctx, course); err != nil {
+			return err
+		}
+
+		restored, err = c.revise(ctx, course, &revision)
+		if err != nil {
+			return err
+		}
+
+		return c.record(ctx, model.AuditCourseRolledBack, &before, course)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
+// revise stores the current state of course as its next revision.
+func (c *CourseService) revise(ctx context.Context, course *model.Course, rollbackOf *int) (*model.CourseRevision, error) {
+	revision := model.NewCourseRevision(course, authz.Actor(ctx), rollbackOf)
+	if err := c.revisions.CreateCourseRevision(ctx, revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// record appends the audit entry of a mutation and counts its event once the
+// outermost transaction commits. It runs in the mutation's transaction, so a
+// change is never committed without its audit entry, and a change rolled
+// back by an enclosing transaction (an atomic batch, a seed) is not counted.
+func (c *CourseService) record(ctx context.Context, action model.AuditAction, before, after *model.Course) error {
+	entry, err := model.NewAuditEntry(model.NewAuditEntryInput{
+		Action:    action,
+		Actor:     authz.Actor(ctx),
+		CoActors:  authz.CoActors(ctx),
+		RequestID: web.GetRequestID(ctx),
+		Before:    before,
+		After:     after,
+	})
+	if err != nil {
+		return fault.Wrap(err, "failed to build audit entry", fault.WithCode(fault.Internal))
+	}
+
+	if err := c.audit.AppendAuditEntry(ctx, entry); err != nil {
+		return err
+	}
+	c.transactor.AfterCommit(ctx, func() { c.metrics.CountEvent(string(action)) })
+
+	return nil
+}
@@ -0,0 +1,116 @@
+//go:build unit
+
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/mocks"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	service "github.com/marcelofabianov/dojo-go/internal/service"
+)
+
+func TestCourseService_Audit(t *testing.T) {
+	courseID := "0199a000-0000-7000-8000-00000000000a"
+	existing := func() *model.Course {
+		return &model.Course{ID: courseID, Title: "Go", Description: "Go course", CreatedAt: time.Now()}
+	}
+
+	ctx := authz.WithPrincipal(context.Background(), authz.Principal{ID: "user-1"})
+	ctx = context.WithValue(ctx, middleware.RequestIDKey, "req-1")
+
+	setupAudit := func() (*mocks.MockCourseRepository, *mocks.MockAuditRepository, *mocks.FakeTransactor, *[]*model.AuditEntry) {
+		repo := new(mocks.MockCourseRepository)
+		repo.On("CreateCourse", mock.Anything, mock.Anything).Return(nil).Maybe()
+		repo.On("GetCourseByID", mock.Anything, courseID).Return(existing(), nil).Maybe()
+		repo.On("UpdateCourse", mock.Anything, mock.Anything).Return(nil).Maybe()
+		repo.On("DeleteCourseByID", mock.Anything, courseID).Return(nil).Maybe()
+
+		var entries []*model.AuditEntry
+		audit := new(mocks.MockAuditRepository)
+		audit.On("AppendAuditEntry", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			entries = append(entries, args.Get(1).(*model.AuditEntry))
+		}).Return(nil).Maybe()
+
+		return repo, audit, &mocks.FakeTransactor{}, &entries
+	}
+
+	t.Run("should record the actor, request id and created state", func(t *testing.T) {
+		repo, audit, tx, entries := setupAudit()
+		metrics := &mocks.FakeMetrics{}
+		svc := service.NewCourseService(repo, mocks.NewCourseRevisionRepositoryStub(), audit, tx, metrics, authz.NewPolicy(&config.AuthzConfig{}))
+
+		course, err := svc.CreateCourse(ctx, model.NewCourseInput{Title: "Go", Description: "Go course"})
+		require.NoError(t, err)
+
+		require.Len(t, *entries, 1)
+		entry := (*entries)[0]
+		assert.Equal(t, model.AuditCourseCreated, entry.Action)
+		assert.Equal(t, course.ID, entry.CourseID)
+		assert.Equal(t, "user-1", entry.Actor)
+		assert.Equal(t, "req-1", entry.RequestID)
+		assert.Nil(t, entry.Before)
+		assert.Equal(t, "Go", entry.After.Title)
+		assert.Equal(t, "Go", *entry.Changes["title"].To)
+		assert.Nil(t, entry.Changes["title"].From)
+		assert.True(t, tx.Committed)
+		assert.Equal(t, []string{"course.created"}, metrics.Events)
+	})
+
+	t.Run("should record only the changed fields of an update", func(t *testing.T) {
+		repo, audit, tx, entries := setupAudit()
+		svc := service.NewCourseService(repo, mocks.NewCourseRevisionRepositoryStub(), audit, tx, &mocks.FakeMetrics{}, authz.NewPolicy(&config.AuthzConfig{}))
+
+		_, err := svc.UpdateCourse(ctx, courseID, model.UpdateCourseInput{Title: "Go 2", Description: "Go course"})
+		require.NoError(t, err)
+
+		require.Len(t, *entries, 1)
+		entry := (*entries)[0]
+		assert.Equal(t, model.AuditCourseUpdated, entry.Action)
+		assert.Equal(t, "Go", entry.Before.Title)
+		assert.Equal(t, "Go 2", entry.After.Title)
+		require.Len(t, entry.Changes, 1)
+		assert.Equal(t, "Go", *entry.Changes["title"].From)
+		assert.Equal(t, "Go 2", *entry.Changes["title"].To)
+	})
+
+	t.Run("should record the deleted state", func(t *testing.T) {
+		repo, audit, tx, entries := setupAudit()
+		svc := service.NewCourseService(repo, mocks.NewCourseRevisionRepositoryStub(), audit, tx, &mocks.FakeMetrics{}, authz.NewPolicy(&config.AuthzConfig{}))
+
+		require.NoError(t, svc.DeleteCourseByID(authz.WithPrincipal(context.Background(), authz.SystemPrincipal("collab")), courseID))
+
+		require.Len(t, *entries, 1)
+		entry := (*entries)[0]
+		assert.Equal(t, model.AuditCourseDeleted, entry.Action)
+		assert.Equal(t, courseID, entry.CourseID)
+		assert.Equal(t, "system:collab", entry.Actor)
+		assert.Equal(t, "Go", entry.Before.Title)
+		assert.Nil(t, entry.After)
+	})
+
+	t.Run("should roll back the change when the audit entry cannot be written", func(t *testing.T) {
+		repo, _, tx, _ := setupAudit()
+		audit := new(mocks.MockAuditRepository)
+		audit.On("AppendAuditEntry", mock.Anything, mock.Anything).Return(errors.New("disk full"))
+		metrics := &mocks.FakeMetrics{}
+		svc := service.NewCourseService(repo, mocks.NewCourseRevisionRepositoryStub(), audit, tx, metrics, authz.NewPolicy(&config.AuthzConfig{}))
+
+		_, err := svc.UpdateCourse(ctx, courseID, model.UpdateCourseInput{Title: "Go 2", Description: "Go course"})
+
+		assert.Error(t, err)
+		assert.True(t, tx.RolledBack)
+		assert.False(t, tx.Committed)
+		assert.Empty(t, metrics.Events)
+	})
+}
@@ -0,0 +1,104 @@
+//go:build unit
+
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/mocks"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	service "github.com/marcelofabianov/dojo-go/internal/service"
+)
+
+func TestCourseService_Authorization(t *testing.T) {
+	courseID := "0199a000-0000-7000-8000-00000000000a"
+
+	cfg := &config.AuthzConfig{
+		Enabled: true,
+		Roles: map[string][]string{
+			"viewer": {"course:read"},
+			"editor": {"course:read", "course:create", "course:update"},
+			"admin":  {"course:read", "course:create", "course:update", "course:delete"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		operation string
+		wantCode  fault.Code
+	}{
+		{"viewer can read", withRoles("viewer"), "read", ""},
+		{"viewer cannot create", withRoles("viewer"), "create", fault.Forbidden},
+		{"viewer cannot update", withRoles("viewer"), "update", fault.Forbidden},
+		{"viewer cannot delete", withRoles("viewer"), "delete", fault.Forbidden},
+		{"editor can read", withRoles("editor"), "read", ""},
+		{"editor can create", withRoles("editor"), "create", ""},
+		{"editor can update", withRoles("editor"), "update", ""},
+		{"editor cannot delete", withRoles("editor"), "delete", fault.Forbidden},
+		{"admin can delete", withRoles("admin"), "delete", ""},
+		{"any granting role is enough", withRoles("viewer", "admin"), "delete", ""},
+		{"unknown role is denied", withRoles("guest"), "read", fault.Forbidden},
+		{"principal without roles is denied", withRoles(), "read", fault.Forbidden},
+		{"system principal can delete", authz.WithPrincipal(context.Background(), authz.SystemPrincipal("worker")), "delete", ""},
+		{"anonymous caller must authenticate", context.Background(), "read", fault.Unauthorized},
+		{"anonymous caller cannot delete", context.Background(), "delete", fault.Unauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockCourseRepository)
+			repo.On("CreateCourse", mock.Anything, mock.Anything).Return(nil).Maybe()
+			repo.On("GetCourseByID", mock.Anything, courseID).
+				Return(&model.Course{ID: courseID, Title: "Go", Description: "Go course", CreatedAt: time.Now()}, nil).Maybe()
+			repo.On("UpdateCourse", mock.Anything, mock.Anything).Return(nil).Maybe()
+			repo.On("DeleteCourseByID", mock.Anything, courseID).Return(nil).Maybe()
+
+			svc := service.NewCourseService(repo, mocks.NewCourseRevisionRepositoryStub(), mocks.NewAuditRepositoryStub(), &mocks.FakeTransactor{}, &mocks.FakeMetrics{}, authz.NewPolicy(cfg))
+
+			var err error
+			switch tt.operation {
+			case "read":
+				_, err = svc.GetCourseByID(tt.ctx, courseID)
+			case "create":
+				_, err = svc.CreateCourse(tt.ctx, model.NewCourseInput{Title: "Go", Description: "Go course"})
+			case "update":
+				_, err = svc.UpdateCourse(tt.ctx, courseID, model.UpdateCourseInput{Title: "Go 2", Description: "Go course"})
+			case "delete":
+				err = svc.DeleteCourseByID(tt.ctx, courseID)
+			}
+
+			if tt.wantCode == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, fault.IsCode(err, tt.wantCode), "expected %s, got %v", tt.wantCode, err)
+			repo.AssertNotCalled(t, "CreateCourse", mock.Anything, mock.Anything)
+			repo.AssertNotCalled(t, "UpdateCourse", mock.Anything, mock.Anything)
+			repo.AssertNotCalled(t, "DeleteCourseByID", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("disabled policy allows anonymous callers", func(t *testing.T) {
+		repo := new(mocks.MockCourseRepository)
+		repo.On("GetCourseByID", mock.Anything, courseID).Return(&model.Course{ID: courseID}, nil)
+		repo.On("DeleteCourseByID", mock.Anything, courseID).Return(nil)
+
+		svc := service.NewCourseService(repo, mocks.NewCourseRevisionRepositoryStub(), mocks.NewAuditRepositoryStub(), &mocks.FakeTransactor{}, &mocks.FakeMetrics{}, authz.NewPolicy(&config.AuthzConfig{Enabled: false}))
+
+		assert.NoError(t, svc.DeleteCourseByID(context.Background(), courseID))
+	})
+}
+
+func withRoles(roles ...string) context.Context {
+	return authz.WithPrincipal(context.Background(), authz.Principal{ID: "user-1", Roles: roles})
+}
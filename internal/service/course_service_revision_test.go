@@ -0,0 +1,109 @@
+//go:build unit
+
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/authz"
+	"github.com/marcelofabianov/dojo-go/internal/mocks"
+	"github.com/marcelofabianov/dojo-go/internal/model"
+	"github.com/marcelofabianov/dojo-go/internal/port"
+	service "github.com/marcelofabianov/dojo-go/internal/service"
+)
+
+func TestCourseService_Revisions(t *testing.T) {
+	courseID := "0199a000-0000-7000-8000-00000000000a"
+	ctx := authz.WithPrincipal(context.Background(), authz.Principal{ID: "user-1"})
+
+	first := &model.CourseRevision{CourseID: courseID, Revision: 1, Title: "Go", Description: "Go course", Actor: "user-1", CreatedAt: time.Now()}
+	second := &model.CourseRevision{CourseID: courseID, Revision: 2, Title: "Go 2", Description: "Go course", Actor: "user-2", CreatedAt: time.Now()}
+
+	setup := func() (*mocks.MockCourseRepository, *mocks.MockCourseRevisionRepository, *mocks.MockAuditRepository, *mocks.FakeTransactor, port.CourseServicePort) {
+		repo := new(mocks.MockCourseRepository)
+		revisions := new(mocks.MockCourseRevisionRepository)
+		audit := mocks.NewAuditRepositoryStub()
+		tx := &mocks.FakeTransactor{}
+		return repo, revisions, audit, tx, service.NewCourseService(repo, revisions, audit, tx, &mocks.FakeMetrics{}, authz.NewPolicy(&config.AuthzConfig{}))
+	}
+
+	t.Run("should store a revision on every update", func(t *testing.T) {
+		repo, revisions, _, _, svc := setup()
+		repo.On("GetCourseByID", mock.Anything, courseID).Return(&model.Course{ID: courseID, Title: "Go", Description: "Go course"}, nil)
+		repo.On("UpdateCourse", mock.Anything, mock.Anything).Return(nil)
+		revisions.On("CreateCourseRevision", mock.Anything, mock.MatchedBy(func(r *model.CourseRevision) bool {
+			return r.CourseID == courseID && r.Title == "Go 2" && r.Actor == "user-1" && r.RollbackOf == nil
+		})).Return(nil).Once()
+
+		_, err := svc.UpdateCourse(ctx, courseID, model.UpdateCourseInput{Title: "Go 2", Description: "Go course"})
+
+		require.NoError(t, err)
+		revisions.AssertExpectations(t)
+	})
+
+	t.Run("should report a course without revisions as not found", func(t *testing.T) {
+		_, revisions, _, _, svc := setup()
+		revisions.On("ListCourseRevisions", mock.Anything, courseID).Return([]*model.CourseRevision{}, nil)
+
+		_, err := svc.ListCourseRevisions(ctx, courseID)
+
+		assert.ErrorIs(t, err, model.ErrCourseNotFound)
+	})
+
+	t.Run("should diff two revisions field by field", func(t *testing.T) {
+		_, revisions, _, _, svc := setup()
+		revisions.On("GetCourseRevision", mock.Anything, courseID, 1).Return(first, nil)
+		revisions.On("GetCourseRevision", mock.Anything, courseID, 2).Return(second, nil)
+
+		changes, err := svc.DiffCourseRevisions(ctx, courseID, 1, 2)
+
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "Go", *changes["title"].From)
+		assert.Equal(t, "Go 2", *changes["title"].To)
+	})
+
+	t.Run("should roll back by creating a new revision", func(t *testing.T) {
+		repo, revisions, audit, tx, svc := setup()
+		revisions.On("GetCourseRevision", mock.Anything, courseID, 1).Return(first, nil)
+		repo.On("GetCourseByID", mock.Anything, courseID).Return(&model.Course{ID: courseID, Title: "Go 2", Description: "Go course"}, nil)
+		repo.On("UpdateCourse", mock.Anything, mock.MatchedBy(func(c *model.Course) bool {
+			return c.Title == "Go"
+		})).Return(nil).Once()
+		revisions.On("CreateCourseRevision", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(1).(*model.CourseRevision).Revision = 3
+		}).Return(nil).Once()
+
+		restored, err := svc.RollbackCourse(ctx, courseID, 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, restored.Revision)
+		assert.Equal(t, "Go", restored.Title)
+		require.NotNil(t, restored.RollbackOf)
+		assert.Equal(t, 1, *restored.RollbackOf)
+		assert.True(t, tx.Committed)
+		assert.Equal(t, sql.LevelSerializable, tx.Options.Isolation)
+		audit.AssertCalled(t, "AppendAuditEntry", mock.Anything, mock.MatchedBy(func(e *model.AuditEntry) bool {
+			return e.Action == model.AuditCourseRolledBack
+		}))
+	})
+
+	t.Run("should not roll back to a missing revision", func(t *testing.T) {
+		repo, revisions, _, tx, svc := setup()
+		revisions.On("GetCourseRevision", mock.Anything, courseID, 9).Return(nil, model.ErrRevisionNotFound)
+
+		_, err := svc.RollbackCourse(ctx, courseID, 9)
+
+		assert.ErrorIs(t, err, model.ErrRevisionNotFound)
+		assert.True(t, tx.RolledBack)
+		repo.AssertNotCalled(t, "UpdateCourse", mock.Anything, mock.Anything)
+	})
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/internal/authz"
 	"github.com/marcelofabianov/dojo-go/internal/mocks"
 	"github.com/marcelofabianov/dojo-go/internal/model"
 	"github.com/marcelofabianov/dojo-go/internal/port"
@@ -18,16 +20,22 @@ import (
 )
 
 type courseServiceTestSuite struct {
-	repoMock *mocks.MockCourseRepository
-	service  port.CourseServicePort
+	repoMock  *mocks.MockCourseRepository
+	auditMock *mocks.MockAuditRepository
+	tx        *mocks.FakeTransactor
+	service   port.CourseServicePort
 }
 
 func setup() *courseServiceTestSuite {
 	repoMock := new(mocks.MockCourseRepository)
-	svc := service.NewCourseService(repoMock)
+	auditMock := mocks.NewAuditRepositoryStub()
+	tx := &mocks.FakeTransactor{}
+	svc := service.NewCourseService(repoMock, mocks.NewCourseRevisionRepositoryStub(), auditMock, tx, &mocks.FakeMetrics{}, authz.NewPolicy(&config.AuthzConfig{}))
 	return &courseServiceTestSuite{
-		repoMock: repoMock,
-		service:  svc,
+		repoMock:  repoMock,
+		auditMock: auditMock,
+		tx:        tx,
+		service:   svc,
 	}
 }
 
@@ -111,6 +119,32 @@ func TestCourseService_GetCourseByID(t *testing.T) {
 	})
 }
 
+func TestCourseService_ListCourses(t *testing.T) {
+	t.Run("should list courses up to the limit", func(t *testing.T) {
+		s := setup()
+		ctx := context.Background()
+		courses := []*model.Course{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}}
+
+		s.repoMock.On("ListCourses", mock.Anything, 2).Return(courses, nil)
+
+		result, err := s.service.ListCourses(ctx, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, courses, result)
+		s.repoMock.AssertExpectations(t)
+	})
+
+	t.Run("should reject a limit that is not positive", func(t *testing.T) {
+		s := setup()
+
+		result, err := s.service.ListCourses(context.Background(), 0)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		s.repoMock.AssertNotCalled(t, "ListCourses", mock.Anything, mock.Anything)
+	})
+}
+
 func TestCourseService_UpdateCourse(t *testing.T) {
 	t.Run("should update course successfully", func(t *testing.T) {
 		s := setup()
@@ -176,6 +210,7 @@ func TestCourseService_DeleteCourseByID(t *testing.T) {
 		ctx := context.Background()
 		courseID := "test-id"
 
+		s.repoMock.On("GetCourseByID", mock.Anything, courseID).Return(&model.Course{ID: courseID}, nil)
 		s.repoMock.On("DeleteCourseByID", mock.Anything, courseID).Return(nil)
 
 		err := s.service.DeleteCourseByID(ctx, courseID)
@@ -189,12 +224,12 @@ func TestCourseService_DeleteCourseByID(t *testing.T) {
 		ctx := context.Background()
 		courseID := "not-found-id"
 
-		s.repoMock.On("DeleteCourseByID", mock.Anything, courseID).Return(model.ErrCourseNotFound)
+		s.repoMock.On("GetCourseByID", mock.Anything, courseID).Return(nil, model.ErrCourseNotFound)
 
 		err := s.service.DeleteCourseByID(ctx, courseID)
 
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, model.ErrCourseNotFound)
-		s.repoMock.AssertExpectations(t)
+		s.repoMock.AssertNotCalled(t, "DeleteCourseByID", mock.Anything, mock.Anything)
 	})
 }
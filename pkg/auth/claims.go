@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type contextKey string
+
+const (
+	claimsCtxKey        = contextKey("claims")
+	authenticatedCtxKey = contextKey("authenticated")
+)
+
+// Claims are the JWT claims the API understands. Scope follows RFC 8693
+// (space separated), Roles and Tenant are custom claims.
+type Claims struct {
+	jwt.RegisteredClaims
+	Scope  string   `json:"scope,omitempty"`
+	Roles  []string `json:"roles,omitempty"`
+	Tenant string   `json:"tenant,omitempty"`
+}
+
+func (c *Claims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return MarkAuthenticated(context.WithValue(ctx, claimsCtxKey, claims))
+}
+
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsCtxKey).(*Claims)
+	return claims, ok
+}
+
+// MarkAuthenticated records that the request was authenticated, so that
+// other authentication schemes running later in the chain skip it.
+func MarkAuthenticated(ctx context.Context) context.Context {
+	return context.WithValue(ctx, authenticatedCtxKey, true)
+}
+
+func IsAuthenticated(ctx context.Context) bool {
+	authenticated, _ := ctx.Value(authenticatedCtxKey).(bool)
+	return authenticated
+}
@@ -0,0 +1,251 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+)
+
+const (
+	jwksFetchTimeout = 5 * time.Second
+	// jwksMinRefresh bounds how often an unknown kid or a failed load can
+	// trigger a refetch.
+	jwksMinRefresh = 30 * time.Second
+)
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// KeySet holds the public keys of a JWKS document loaded from a file or URL.
+// Keys are reloaded after the refresh interval, or sooner when a token
+// references a kid the set does not know, so key rotation needs no restart.
+// A single load runs at a time, and after a failed one requests fail fast
+// with its error until jwksMinRefresh has passed.
+type KeySet struct {
+	file        string
+	url         string
+	refresh     time.Duration
+	client      *http.Client
+	loading     sync.Mutex
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	loadedAt    time.Time
+	attemptedAt time.Time
+	lastErr     error
+}
+
+func NewKeySet(file, url string, refresh time.Duration) *KeySet {
+	return &KeySet{
+		file:    file,
+		url:     url,
+		refresh: refresh,
+		client:  &http.Client{Timeout: jwksFetchTimeout},
+		keys:    make(map[string]crypto.PublicKey),
+	}
+}
+
+// Key returns the public key for kid, reloading the set when needed.
+func (s *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	stale := time.Since(s.loadedAt) > s.refresh
+	attemptedAt, lastErr := s.attemptedAt, s.lastErr
+	s.mu.RUnlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+
+	// A stale set is reloaded right away unless the last load failed; an
+	// unknown kid or a failing source waits for jwksMinRefresh.
+	if (stale && lastErr == nil) || time.Since(attemptedAt) > jwksMinRefresh {
+		lastErr = s.reload(ctx, attemptedAt)
+	}
+
+	if lastErr != nil {
+		if ok {
+			// Keep serving the cached key while the source is unavailable.
+			return key, nil
+		}
+		return nil, lastErr
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok = s.keys[kid]
+	if !ok {
+		return nil, fault.New("unknown signing key", fault.WithCode(fault.Unauthorized), fault.WithContext("kid", kid))
+	}
+	return key, nil
+}
+
+// reload loads the set unless another request did since attemptedAt, in
+// which case it returns the outcome of that load. Readers of cached keys are
+// not blocked while the source is read.
+func (s *KeySet) reload(ctx context.Context, attemptedAt time.Time) error {
+	s.loading.Lock()
+	defer s.loading.Unlock()
+
+	s.mu.RLock()
+	reloaded, lastErr := !s.attemptedAt.Equal(attemptedAt), s.lastErr
+	s.mu.RUnlock()
+	if reloaded {
+		return lastErr
+	}
+
+	// The result is shared with every waiting request, so it must not depend
+	// on this one being canceled.
+	keys, err := s.load(context.WithoutCancel(ctx))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attemptedAt = time.Now()
+	s.lastErr = err
+	if err == nil {
+		s.keys = keys
+		s.loadedAt = s.attemptedAt
+	}
+	return err
+}
+
+func (s *KeySet) load(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	raw, err := s.read(ctx)
+	if err != nil {
+		return nil, fault.Wrap(err, "failed to load jwks", fault.WithCode(fault.InfraError))
+	}
+
+	keys, err := parseJWKS(raw)
+	if err != nil {
+		return nil, fault.Wrap(err, "failed to parse jwks", fault.WithCode(fault.InfraError))
+	}
+
+	return keys, nil
+}
+
+func (s *KeySet) read(ctx context.Context) ([]byte, error) {
+	if s.file != "" {
+		return os.ReadFile(s.file)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected jwks status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+func parseJWKS(raw []byte) (map[string]crypto.PublicKey, error) {
+	var set jwkSet
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch k.Kty {
+		case "RSA":
+			key, err = rsaKey(k)
+		case "EC":
+			key, err = ecKey(k)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func rsaKey(k jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+func ecKey(k jwk) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if _, err := key.ECDH(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
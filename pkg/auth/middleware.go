@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type Middleware struct {
+	enabled  bool
+	verifier *Verifier
+}
+
+func NewMiddleware(cfg *config.ServerConfig, verifier *Verifier) *Middleware {
+	return &Middleware{
+		enabled:  cfg.Auth.Enabled,
+		verifier: verifier,
+	}
+}
+
+// Handler requires a valid bearer token and stores its claims in the request
+// context, adding the subject to the request logger. Requests already
+// authenticated by another scheme are passed through. Browsers cannot set
+// headers on EventSource and WebSocket connections, so those requests may
+// send the token in the access_token query parameter instead.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.enabled || IsAuthenticated(r.Context()) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		logger := web.GetLogger(r.Context())
+
+		token := bearerToken(r)
+		if token == "" {
+			logger.Warn("missing bearer token")
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			web.Error(w, r, fault.New("missing bearer token", fault.WithCode(fault.Unauthorized)))
+			return
+		}
+
+		claims, err := m.verifier.Verify(r.Context(), token)
+		if err != nil {
+			logger.Warn("bearer token rejected", "error", err)
+			if fErr, ok := fault.AsFault(err); ok && fErr.Code == fault.Unauthorized {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			}
+			web.Error(w, r, err)
+			return
+		}
+
+		ctx := WithClaims(r.Context(), claims)
+		ctx = web.WithLoggerAttrs(ctx, "subject", claims.Subject)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func bearerToken(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return ""
+		}
+		return strings.TrimSpace(token)
+	}
+
+	if r.Method == http.MethodGet && (strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")) {
+		return r.URL.Query().Get("access_token")
+	}
+
+	return ""
+}
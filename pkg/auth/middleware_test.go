@@ -0,0 +1,243 @@
+//go:build unit
+
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/auth"
+)
+
+const (
+	testIssuer   = "http://localhost:8081"
+	testAudience = "dojo-go"
+	testSecret   = "test-secret"
+)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func claims(subject string, expiresIn time.Duration) *auth.Claims {
+	return &auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			Issuer:    testIssuer,
+			Audience:  jwt.ClaimStrings{testAudience},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		},
+		Roles: []string{"editor"},
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, c *auth.Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, c)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func newAuthHandler(t *testing.T, authCfg config.AuthConfig) (http.Handler, *auth.Claims) {
+	t.Helper()
+
+	authCfg.Issuer = testIssuer
+	authCfg.Audience = testAudience
+	authCfg.JWKSRefreshInterval = time.Minute
+	cfg := &config.ServerConfig{Auth: authCfg}
+
+	verifier, err := auth.NewVerifier(cfg)
+	require.NoError(t, err)
+
+	var got auth.Claims
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, ok := auth.ClaimsFromContext(r.Context()); ok {
+			got = *c
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return auth.NewMiddleware(cfg, verifier).Handler(next), &got
+}
+
+func do(h http.Handler, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/courses/id", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware_HS256(t *testing.T) {
+	h, got := newAuthHandler(t, config.AuthConfig{Enabled: true, HMACSecret: testSecret, ClockSkew: time.Minute})
+
+	t.Run("should accept a valid token and expose its claims", func(t *testing.T) {
+		rec := do(h, sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), claims("user-1", time.Hour)))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "user-1", got.Subject)
+		assert.Equal(t, []string{"editor"}, got.Roles)
+	})
+
+	t.Run("should tolerate expiry within the clock skew", func(t *testing.T) {
+		rec := do(h, sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), claims("user-1", -30*time.Second)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	tests := []struct {
+		name  string
+		token func() string
+	}{
+		{"missing token", func() string { return "" }},
+		{"expired token", func() string {
+			return sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), claims("user-1", -2*time.Minute))
+		}},
+		{"wrong secret", func() string {
+			return sign(t, jwt.SigningMethodHS256, "", []byte("other"), claims("user-1", time.Hour))
+		}},
+		{"wrong audience", func() string {
+			c := claims("user-1", time.Hour)
+			c.Audience = jwt.ClaimStrings{"other"}
+			return sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), c)
+		}},
+		{"wrong issuer", func() string {
+			c := claims("user-1", time.Hour)
+			c.Issuer = "other"
+			return sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), c)
+		}},
+		{"unsigned token", func() string {
+			return sign(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, claims("user-1", time.Hour))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run("should reject "+tt.name, func(t *testing.T) {
+			rec := do(h, tt.token())
+
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
+
+func TestMiddleware_JWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kid": "rsa-1", "kty": "RSA", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kid": "ec-1", "kty": "EC", "use": "sig", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+	}})
+	require.NoError(t, err)
+
+	t.Run("should verify RS256 tokens against a jwks file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "jwks.json")
+		require.NoError(t, os.WriteFile(file, jwks, 0o600))
+
+		h, got := newAuthHandler(t, config.AuthConfig{Enabled: true, JWKSFile: file})
+
+		rec := do(h, sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims("user-2", time.Hour)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "user-2", got.Subject)
+
+		rec = do(h, sign(t, jwt.SigningMethodRS256, "unknown", rsaKey, claims("user-2", time.Hour)))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("should verify ES256 tokens against a jwks url", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(jwks)
+		}))
+		defer srv.Close()
+
+		h, got := newAuthHandler(t, config.AuthConfig{Enabled: true, JWKSURL: srv.URL})
+
+		rec := do(h, sign(t, jwt.SigningMethodES256, "ec-1", ecKey, claims("user-3", time.Hour)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "user-3", got.Subject)
+	})
+
+	t.Run("should fail fast while the jwks url is down", func(t *testing.T) {
+		var hits atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			time.Sleep(50 * time.Millisecond)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		h, _ := newAuthHandler(t, config.AuthConfig{Enabled: true, JWKSURL: srv.URL})
+		token := sign(t, jwt.SigningMethodRS256, "rsa-2", rsaKey, claims("user-2", time.Hour))
+
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Go(func() {
+				assert.Equal(t, http.StatusBadGateway, do(h, token).Code)
+			})
+		}
+		wg.Wait()
+		assert.Equal(t, http.StatusBadGateway, do(h, token).Code)
+
+		assert.Equal(t, int32(1), hits.Load())
+	})
+
+	t.Run("should reject HS256 tokens when only a jwks source is configured", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "jwks.json")
+		require.NoError(t, os.WriteFile(file, jwks, 0o600))
+
+		h, _ := newAuthHandler(t, config.AuthConfig{Enabled: true, JWKSFile: file})
+
+		rec := do(h, sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), claims("user-1", time.Hour)))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestMiddleware_PassThrough(t *testing.T) {
+	t.Run("should not require a token when auth is disabled", func(t *testing.T) {
+		h, _ := newAuthHandler(t, config.AuthConfig{Enabled: false})
+		assert.Equal(t, http.StatusOK, do(h, "").Code)
+	})
+
+	t.Run("should skip requests already authenticated by another scheme", func(t *testing.T) {
+		h, _ := newAuthHandler(t, config.AuthConfig{Enabled: true, HMACSecret: testSecret})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/courses/id", nil)
+		req = req.WithContext(auth.MarkAuthenticated(req.Context()))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should fail to start when enabled without key material", func(t *testing.T) {
+		_, err := auth.NewVerifier(&config.ServerConfig{Auth: config.AuthConfig{Enabled: true}})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+)
+
+type Verifier struct {
+	secret []byte
+	keys   *KeySet
+	parser *jwt.Parser
+}
+
+func NewVerifier(cfg *config.ServerConfig) (*Verifier, error) {
+	authCfg := cfg.Auth
+
+	v := &Verifier{}
+	methods := make([]string, 0, 3)
+
+	if authCfg.HMACSecret != "" {
+		v.secret = []byte(authCfg.HMACSecret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if authCfg.JWKSFile != "" || authCfg.JWKSURL != "" {
+		v.keys = NewKeySet(authCfg.JWKSFile, authCfg.JWKSURL, authCfg.JWKSRefreshInterval)
+		methods = append(methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+
+	if authCfg.Enabled && len(methods) == 0 {
+		return nil, fault.New("auth is enabled but neither an hmac secret nor a jwks source is configured",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithLeeway(authCfg.ClockSkew),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if authCfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(authCfg.Issuer))
+	}
+	if authCfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(authCfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+
+	return v, nil
+}
+
+// Verify checks the token signature and registered claims and returns its
+// claims. Invalid tokens yield an Unauthorized fault; a JWKS source that
+// cannot be reached yields an InfraError fault.
+func (v *Verifier) Verify(ctx context.Context, raw string) (*Claims, error) {
+	claims := &Claims{}
+
+	_, err := v.parser.ParseWithClaims(raw, claims, func(token *jwt.Token) (any, error) {
+		switch token.Method.Alg() {
+		case jwt.SigningMethodHS256.Alg():
+			return v.secret, nil
+		default:
+			kid, _ := token.Header["kid"].(string)
+			return v.keys.Key(ctx, kid)
+		}
+	})
+	if err != nil {
+		if fErr, ok := fault.AsFault(err); ok && fErr.Code == fault.InfraError {
+			return nil, fErr
+		}
+
+		reason := "invalid token"
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			reason = "token has expired"
+		}
+		return nil, fault.Wrap(err, reason, fault.WithCode(fault.Unauthorized))
+	}
+
+	return claims, nil
+}
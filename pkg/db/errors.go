@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+// SQLSTATE codes read by classify.
+const (
+	queryCanceled    = "57014"
+	lockNotAvailable = "55P03"
+	adminShutdown    = "57P01"
+	cannotConnectNow = "57P03"
+)
+
+// classify gives err the web.Timeout code when a deadline ran out or
+// Postgres cancelled the statement on statement_timeout, and the
+// web.Unavailable code when Postgres gave up waiting for a lock or is
+// shutting down. Other errors are returned as they are.
+//
+// A statement cancelled because the caller went away fails with 57014 too;
+// ctx tells it apart, and it is not reported as a timeout.
+func classify(ctx context.Context, err error) error {
+	if err == nil || errors.Is(ctx.Err(), context.Canceled) {
+		return err
+	}
+
+	var pgErr *pgconn.PgError
+	errors.As(err, &pgErr)
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), pgErr != nil && pgErr.Code == queryCanceled:
+		return fault.Wrap(err,
+			"the operation timed out",
+			fault.WithCode(web.Timeout),
+		)
+	case pgErr != nil && (pgErr.Code == lockNotAvailable || pgErr.Code == adminShutdown || pgErr.Code == cannotConnectNow):
+		return fault.Wrap(err,
+			"the database is unavailable, try again later",
+			fault.WithCode(web.Unavailable),
+		)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,54 @@
+//go:build unit
+
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/marcelofabianov/fault"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+func TestClassify(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		err  error
+		code fault.Code
+	}{
+		{"deadline exceeded", context.DeadlineExceeded, web.Timeout},
+		{"statement timeout", &pgconn.PgError{Code: "57014"}, web.Timeout},
+		{"lock timeout", &pgconn.PgError{Code: "55P03"}, web.Unavailable},
+		{"server shutting down", &pgconn.PgError{Code: "57P01"}, web.Unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classify(ctx, tt.err)
+			assert.True(t, fault.IsCode(err, tt.code))
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	t.Run("should leave other errors alone", func(t *testing.T) {
+		assert.NoError(t, classify(ctx, nil))
+
+		for _, err := range []error{&pgconn.PgError{Code: "23505"}, context.Canceled, errors.New("plain")} {
+			assert.Same(t, err, classify(ctx, err))
+		}
+	})
+
+	t.Run("should not report a caller that went away as a timeout", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := &pgconn.PgError{Code: "57014"}
+		assert.Same(t, err, classify(cancelled, err))
+	})
+}
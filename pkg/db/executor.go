@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+// boundedExecutor runs every statement under a deadline: QueryTimeout for
+// reads and ExecTimeout for writes. Statements slower than SlowQuery are
+// logged with the request logger. A zero duration turns its limit off.
+//
+// SELECT statements outside a transaction may go to a read replica (see
+// ReaderFrom); every other statement runs on conn and pins the request to
+// it, so the reads that follow see the write.
+//
+// Errors come back classified (see classify), so timeouts keep their code
+// through the wrapping of the repositories.
+type boundedExecutor struct {
+	conn *sqlx.DB
+	cfg  *config.DBConfig
+}
+
+// Bounded returns an executor over conn, bounded by the timeouts of cfg, for
+// the repositories and stores of the application. It picks the transaction
+// bound to the ctx of each call.
+func Bounded(conn *sqlx.DB, cfg *config.DBConfig) Executor {
+	return &boundedExecutor{conn: conn, cfg: cfg}
+}
+
+func (e *boundedExecutor) ExecContext(ctx context.Context, query string, args ...any) (result sql.Result, err error) {
+	MarkWrite(ctx)
+	bounded, done := e.start(ctx, e.cfg.ExecTimeout, query)
+	defer func() { done(err) }()
+
+	result, err = ExecutorFrom(bounded, e.conn).ExecContext(bounded, query, args...)
+	return result, classify(ctx, err)
+}
+
+func (e *boundedExecutor) NamedExecContext(ctx context.Context, query string, arg any) (result sql.Result, err error) {
+	MarkWrite(ctx)
+	bounded, done := e.start(ctx, e.cfg.ExecTimeout, query)
+	defer func() { done(err) }()
+
+	result, err = ExecutorFrom(bounded, e.conn).NamedExecContext(bounded, query, arg)
+	return result, classify(ctx, err)
+}
+
+func (e *boundedExecutor) GetContext(ctx context.Context, dest any, query string, args ...any) (err error) {
+	exec, timeout := e.route(ctx, query)
+	bounded, done := e.start(ctx, timeout, query)
+	defer func() { done(err) }()
+
+	return classify(ctx, exec.GetContext(bounded, dest, query, args...))
+}
+
+func (e *boundedExecutor) SelectContext(ctx context.Context, dest any, query string, args ...any) (err error) {
+	exec, timeout := e.route(ctx, query)
+	bounded, done := e.start(ctx, timeout, query)
+	defer func() { done(err) }()
+
+	return classify(ctx, exec.SelectContext(bounded, dest, query, args...))
+}
+
+// route tells reads, which may go to a replica, from statements that return
+// rows but write, such as INSERT ... RETURNING.
+func (e *boundedExecutor) route(ctx context.Context, query string) (Executor, time.Duration) {
+	if isRead(query) {
+		return ReaderFrom(ctx, e.conn), e.cfg.QueryTimeout
+	}
+
+	MarkWrite(ctx)
+	return ExecutorFrom(ctx, e.conn), e.cfg.ExecTimeout
+}
+
+func isRead(query string) bool {
+	fields := strings.Fields(query)
+	return len(fields) > 0 && strings.EqualFold(fields[0], "SELECT")
+}
+
+func (e *boundedExecutor) start(ctx context.Context, timeout time.Duration, query string) (context.Context, func(error)) {
+	cancel := context.CancelFunc(func() {})
+	bounded := ctx
+	if timeout > 0 {
+		bounded, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	began := time.Now()
+	return bounded, func(err error) {
+		cancel()
+
+		elapsed := time.Since(began)
+		if e.cfg.SlowQuery <= 0 || elapsed < e.cfg.SlowQuery {
+			return
+		}
+		web.GetLogger(ctx).Warn("slow database query",
+			"statement", strings.Join(strings.Fields(query), " "),
+			"duration", elapsed,
+			"failed", err != nil,
+		)
+	}
+}
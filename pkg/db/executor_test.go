@@ -0,0 +1,89 @@
+//go:build unit
+
+package db_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+func TestBoundedExecutor(t *testing.T) {
+	conn := newNotesDB(t)
+
+	// endless never returns on its own: it counts until interrupted.
+	const endless = `
+		SELECT COUNT(*) FROM (
+			WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n)
+			SELECT i FROM n
+		)
+	`
+
+	t.Run("should stop a read at the query timeout", func(t *testing.T) {
+		cfg := &config.DBConfig{QueryTimeout: 50 * time.Millisecond, ExecTimeout: time.Minute}
+		ctx := context.Background()
+
+		var count int
+		began := time.Now()
+		err := db.Bounded(conn, cfg).GetContext(ctx, &count, endless)
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, fault.IsCode(err, web.Timeout))
+		assert.Less(t, time.Since(began), 5*time.Second)
+	})
+
+	t.Run("should not report a caller that went away as a timeout", func(t *testing.T) {
+		cfg := &config.DBConfig{QueryTimeout: time.Minute}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		var count int
+		err := db.Bounded(conn, cfg).GetContext(ctx, &count, endless)
+		require.True(t, fault.IsCode(err, web.Timeout), "the caller's own deadline is still a timeout")
+
+		gone, leave := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, leave)
+		err = db.Bounded(conn, cfg).GetContext(gone, &count, endless)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.False(t, fault.IsCode(err, web.Timeout))
+	})
+
+	t.Run("should stop a write at the exec timeout", func(t *testing.T) {
+		cfg := &config.DBConfig{QueryTimeout: time.Minute, ExecTimeout: 50 * time.Millisecond}
+		ctx := context.Background()
+		_, err := conn.Exec(`INSERT INTO notes (body) VALUES ('draft')`)
+		require.NoError(t, err)
+
+		_, err = db.Bounded(conn, cfg).ExecContext(ctx, `DELETE FROM notes WHERE body IN (`+endless+`)`)
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("should log statements slower than the threshold", func(t *testing.T) {
+		var logs bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&logs, nil))
+		ctx := context.WithValue(context.Background(), web.LoggerCtxKey, logger)
+
+		fast := &config.DBConfig{SlowQuery: time.Hour}
+		_, err := db.Bounded(conn, fast).ExecContext(ctx, `DELETE FROM notes WHERE body = $1`, "none")
+		require.NoError(t, err)
+		assert.Empty(t, logs.String())
+
+		slow := &config.DBConfig{SlowQuery: time.Nanosecond}
+		_, err = db.Bounded(conn, slow).ExecContext(ctx, `DELETE FROM notes
+			WHERE body = $1`, "none")
+		require.NoError(t, err)
+		assert.Contains(t, logs.String(), "slow database query")
+		assert.Contains(t, logs.String(), `statement="DELETE FROM notes WHERE body = $1"`)
+	})
+}
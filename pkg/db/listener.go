@@ -0,0 +1,187 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+)
+
+const (
+	listenerMinBackoff = 500 * time.Millisecond
+	listenerMaxBackoff = 30 * time.Second
+	// localQueueSize buffers notifications delivered before Run starts.
+	localQueueSize = 256
+)
+
+type NotificationHandler func(payload string)
+
+type notification struct {
+	channel string
+	payload string
+}
+
+// Listener keeps a dedicated connection open for Postgres LISTEN/NOTIFY and
+// dispatches every notification to the handlers registered for its channel.
+// The connection is re-established with exponential backoff when it drops.
+//
+// The memory and sqlite drivers have no LISTEN/NOTIFY: notifications passed
+// to Deliver are dispatched by Run, in order, as if they came from Postgres.
+type Listener struct {
+	dsn      string
+	logger   *slog.Logger
+	mu       sync.RWMutex
+	handlers map[string][]NotificationHandler
+	local    chan notification
+}
+
+func NewListener(cfg *config.DBConfig, logger *slog.Logger) *Listener {
+	l := &Listener{
+		dsn:      DSN(cfg),
+		logger:   logger,
+		handlers: make(map[string][]NotificationHandler),
+	}
+	if cfg.Driver != DriverPostgres {
+		l.local = make(chan notification, localQueueSize)
+	}
+	return l
+}
+
+// Listen registers a handler for a channel. It must be called before Run.
+func (l *Listener) Listen(channel string, handler NotificationHandler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handlers[channel] = append(l.handlers[channel], handler)
+}
+
+func (l *Listener) Run(ctx context.Context) {
+	if l.local != nil {
+		l.runLocal(ctx)
+		return
+	}
+
+	backoff := listenerMinBackoff
+
+	for {
+		connected, err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if connected {
+			backoff = listenerMinBackoff
+		}
+
+		l.logger.Error("database listener disconnected", "error", err, "retry_in", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, listenerMaxBackoff)
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) (bool, error) {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return false, fault.Wrap(err,
+			"failed to open listener connection",
+			fault.WithCode(fault.Internal),
+		)
+	}
+	defer conn.Close(context.Background())
+
+	l.mu.RLock()
+	channels := make([]string, 0, len(l.handlers))
+	for channel := range l.handlers {
+		channels = append(channels, channel)
+	}
+	l.mu.RUnlock()
+
+	for _, channel := range channels {
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return false, fault.Wrap(err,
+				"failed to listen on channel",
+				fault.WithCode(fault.Internal),
+				fault.WithContext("channel", channel),
+			)
+		}
+	}
+
+	l.logger.Info("database listener connected", "channels", channels)
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+
+		l.dispatch(notification.Channel, notification.Payload)
+	}
+}
+
+func (l *Listener) runLocal(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-l.local:
+			l.dispatch(n.channel, n.payload)
+		}
+	}
+}
+
+func (l *Listener) dispatch(channel, payload string) {
+	l.mu.RLock()
+	handlers := l.handlers[channel]
+	l.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(payload)
+	}
+}
+
+// Deliver queues a notification for the handlers of this process, the
+// stand-in for NOTIFY of the drivers without it. It blocks while the queue
+// is full.
+func (l *Listener) Deliver(ctx context.Context, channel, payload string) error {
+	if l.local == nil {
+		return fault.New("listener only delivers notifications without postgres",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("channel", channel),
+		)
+	}
+
+	select {
+	case l.local <- notification{channel: channel, payload: payload}:
+		return nil
+	case <-ctx.Done():
+		return fault.Wrap(ctx.Err(),
+			"failed to notify channel",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("channel", channel),
+		)
+	}
+}
+
+// Notify publishes a payload on a channel through the shared pool. Postgres
+// delivers it to every listener, including the one in this process.
+func Notify(ctx context.Context, db *sqlx.DB, channel, payload string) error {
+	if _, err := db.ExecContext(ctx, "SELECT pg_notify($1, $2)", channel, payload); err != nil {
+		return fault.Wrap(err,
+			"failed to notify channel",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("channel", channel),
+		)
+	}
+	return nil
+}
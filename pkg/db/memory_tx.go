@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+type memoryTx struct {
+	undo  []func()
+	hooks commitHooks
+}
+
+// MemoryTransactor gives in-memory repositories the semantics of
+// Transactor: transactions run one at a time, and the writes made in one are
+// undone, newest first, when fn returns an error or panics. Repositories
+// register how to undo each write with OnRollback. There is no isolation:
+// reads outside the transaction see its writes before it ends, and so there
+// are no serialization failures to retry.
+type MemoryTransactor struct {
+	mu sync.Mutex
+}
+
+func NewMemoryTransactor() *MemoryTransactor {
+	return &MemoryTransactor{}
+}
+
+// WithinTx runs fn in a transaction. Calls nested in an existing transaction
+// behave like a savepoint: a failure undoes only the writes of the nested fn.
+// opts is ignored, transactions already run one at a time.
+func (t *MemoryTransactor) WithinTx(ctx context.Context, _ *sql.TxOptions, fn func(ctx context.Context) error) error {
+	if tx, ok := ctx.Value(txCtxKey{}).(*memoryTx); ok {
+		return tx.run(ctx, fn)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tx := &memoryTx{}
+	if err := tx.run(context.WithValue(ctx, txCtxKey{}, tx), fn); err != nil {
+		return err
+	}
+	tx.hooks.run()
+
+	return nil
+}
+
+// AfterCommit runs fn once the outermost transaction bound to ctx succeeds,
+// like Transactor.AfterCommit.
+func (t *MemoryTransactor) AfterCommit(ctx context.Context, fn func()) {
+	if tx, ok := ctx.Value(txCtxKey{}).(*memoryTx); ok {
+		tx.hooks = append(tx.hooks, fn)
+		return
+	}
+	fn()
+}
+
+// run calls fn and, when it fails, undoes the writes registered since.
+func (tx *memoryTx) run(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	mark, hooks := len(tx.undo), len(tx.hooks)
+	defer func() {
+		p := recover()
+		if err == nil && p == nil {
+			return
+		}
+		for i := len(tx.undo) - 1; i >= mark; i-- {
+			tx.undo[i]()
+		}
+		tx.undo = tx.undo[:mark]
+		tx.hooks.discard(hooks)
+		if p != nil {
+			panic(p)
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// OnRollback registers undo to run if the transaction bound to ctx is rolled
+// back. Outside a transaction writes are final and undo is dropped.
+func OnRollback(ctx context.Context, undo func()) {
+	if tx, ok := ctx.Value(txCtxKey{}).(*memoryTx); ok {
+		tx.undo = append(tx.undo, undo)
+	}
+}
@@ -12,8 +12,38 @@ import (
 	"github.com/marcelofabianov/dojo-go/config"
 )
 
-func NewPostgresConnection(cfg *config.DBConfig, logger *slog.Logger) (*sqlx.DB, error) {
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
+// Drivers accepted in db.driver.
+const (
+	DriverPostgres = "postgres"
+	// DriverSQLite keeps the whole database in the file at db.path.
+	DriverSQLite = "sqlite"
+	// DriverMemory keeps every repository in process, so the API runs with
+	// no database at all. Data is lost when the process exits.
+	DriverMemory = "memory"
+)
+
+// Open connects to the database selected by cfg.Driver. The memory driver
+// has no database: Open returns a nil *sqlx.DB and dependents skip what
+// needs one, such as migrations and the database readiness check.
+func Open(cfg *config.DBConfig, logger *slog.Logger) (*sqlx.DB, error) {
+	switch cfg.Driver {
+	case DriverPostgres:
+		return NewPostgresConnection(cfg, logger)
+	case DriverSQLite:
+		return NewSQLiteConnection(cfg, logger)
+	case DriverMemory:
+		logger.Warn("using the in-memory database, data is lost on restart")
+		return nil, nil
+	default:
+		return nil, fault.New("unsupported database driver",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("driver", cfg.Driver),
+		)
+	}
+}
+
+func DSN(cfg *config.DBConfig) string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
 		cfg.User,
 		cfg.Password,
 		cfg.Host,
@@ -21,8 +51,10 @@ func NewPostgresConnection(cfg *config.DBConfig, logger *slog.Logger) (*sqlx.DB,
 		cfg.Name,
 		cfg.SSLMode,
 	)
+}
 
-	db, err := sqlx.Open("pgx", dsn)
+func NewPostgresConnection(cfg *config.DBConfig, logger *slog.Logger) (*sqlx.DB, error) {
+	db, err := sqlx.Open("pgx", DSN(cfg))
 	if err != nil {
 		return nil, fault.Wrap(err,
 			"failed to open database connection",
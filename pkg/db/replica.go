@@ -0,0 +1,229 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+)
+
+type replica struct {
+	name    string
+	conn    *sqlx.DB
+	healthy atomic.Bool
+}
+
+// ReplicaSet holds the read replicas of db.replicas and which of them are
+// healthy. Reads made in a session (see WithSession) go to a healthy replica,
+// round robin, and fall back to the primary when none is.
+type ReplicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+	interval time.Duration
+	timeout  time.Duration
+	logger   *slog.Logger
+}
+
+// OpenReplicas opens a pool per DSN in cfg.Replicas, with the settings of the
+// primary pool. A replica that does not answer yet starts unhealthy instead
+// of failing startup. Only Postgres has replicas; for the other drivers the
+// set is empty.
+func OpenReplicas(cfg *config.DBConfig, logger *slog.Logger) (*ReplicaSet, error) {
+	s := &ReplicaSet{
+		interval: cfg.ReplicaCheck,
+		timeout:  cfg.QueryTimeout,
+		logger:   logger,
+	}
+	if cfg.Driver != DriverPostgres {
+		return s, nil
+	}
+
+	for i, dsn := range cfg.Replicas {
+		conn, err := sqlx.Open("pgx", dsn)
+		if err != nil {
+			s.Close()
+			return nil, fault.Wrap(err,
+				"failed to open replica connection",
+				fault.WithCode(fault.Internal),
+				fault.WithContext("replica", i),
+			)
+		}
+
+		conn.SetMaxOpenConns(cfg.MaxOpenConns)
+		conn.SetMaxIdleConns(cfg.MaxIdleConns)
+		conn.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		conn.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+		s.Add(conn)
+	}
+
+	if len(s.replicas) > 0 {
+		s.Check(context.Background())
+		logger.Info("read replicas configured", "replicas", len(s.replicas), "healthy", s.Healthy())
+	}
+
+	return s, nil
+}
+
+// Add registers conn as a replica, unhealthy until the next Check.
+func (s *ReplicaSet) Add(conn *sqlx.DB) {
+	s.replicas = append(s.replicas, &replica{
+		name: fmt.Sprintf("replica-%d", len(s.replicas)),
+		conn: conn,
+	})
+}
+
+// Len returns how many replicas are configured, healthy or not.
+func (s *ReplicaSet) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.replicas)
+}
+
+// Healthy returns how many replicas passed the last Check.
+func (s *ReplicaSet) Healthy() int {
+	healthy := 0
+	for _, r := range s.replicas {
+		if r.healthy.Load() {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// pick returns the next healthy replica, or false when there is none.
+func (s *ReplicaSet) pick() (*sqlx.DB, bool) {
+	if s.Len() == 0 {
+		return nil, false
+	}
+
+	start := s.next.Add(1)
+	for i := range uint64(len(s.replicas)) {
+		r := s.replicas[(start+i)%uint64(len(s.replicas))]
+		if r.healthy.Load() {
+			return r.conn, true
+		}
+	}
+	return nil, false
+}
+
+// Check pings every replica and records which answered. Changes are logged,
+// so a replica going down or coming back shows up once.
+func (s *ReplicaSet) Check(ctx context.Context) {
+	for _, r := range s.replicas {
+		err := s.ping(ctx, r.conn)
+
+		healthy := err == nil
+		if r.healthy.Swap(healthy) == healthy {
+			continue
+		}
+		if healthy {
+			s.logger.Info("read replica is healthy, sending reads to it", "replica", r.name)
+		} else {
+			s.logger.Warn("read replica failed its health check, reads fall back to the primary", "replica", r.name, "error", err)
+		}
+	}
+}
+
+func (s *ReplicaSet) ping(ctx context.Context, conn *sqlx.DB) error {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	return conn.PingContext(ctx)
+}
+
+// Run checks the replicas every db.replica_check until ctx is done.
+func (s *ReplicaSet) Run(ctx context.Context) {
+	if s.Len() == 0 || s.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Check(ctx)
+		}
+	}
+}
+
+func (s *ReplicaSet) Close() error {
+	var errs []error
+	for _, r := range s.replicas {
+		errs = append(errs, r.conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Middleware starts a session for each request, so its reads can go to a
+// replica until it writes.
+func (s *ReplicaSet) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithSession(r.Context(), s)))
+	})
+}
+
+type session struct {
+	replicas *ReplicaSet
+	wrote    atomic.Bool
+}
+
+type (
+	sessionCtxKey struct{}
+	primaryCtxKey struct{}
+)
+
+// WithSession starts a read session on ctx, usually one per request. Reads
+// made with ReaderFrom go to a replica of replicas until the session writes
+// or opens a transaction; from then on they go to the primary, so the
+// request reads its own writes whatever the replication lag.
+func WithSession(ctx context.Context, replicas *ReplicaSet) context.Context {
+	return context.WithValue(ctx, sessionCtxKey{}, &session{replicas: replicas})
+}
+
+// MarkWrite pins the session of ctx, if any, to the primary.
+func MarkWrite(ctx context.Context) {
+	if s, ok := ctx.Value(sessionCtxKey{}).(*session); ok {
+		s.wrote.Store(true)
+	}
+}
+
+// Primary sends the reads made with ctx to the primary, whatever the session.
+// Reads that guard access, such as resolving an API key or checking whether it
+// was revoked, must not see a lagging replica.
+func Primary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryCtxKey{}, true)
+}
+
+// ReaderFrom returns where a read-only statement should run: the transaction
+// bound to ctx, a healthy replica when ctx carries a session that has not
+// written and was not sent to the primary, or primary.
+func ReaderFrom(ctx context.Context, primary *sqlx.DB) Executor {
+	if tx, ok := ctx.Value(txCtxKey{}).(*sqlTx); ok {
+		return tx.Tx
+	}
+	if forced, _ := ctx.Value(primaryCtxKey{}).(bool); forced {
+		return primary
+	}
+	if s, ok := ctx.Value(sessionCtxKey{}).(*session); ok && !s.wrote.Load() {
+		if conn, ok := s.replicas.pick(); ok {
+			return conn
+		}
+	}
+	return primary
+}
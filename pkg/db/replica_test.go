@@ -0,0 +1,104 @@
+//go:build unit
+
+package db_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+)
+
+func newReplicaSet(t *testing.T, replicas ...*sqlx.DB) *db.ReplicaSet {
+	t.Helper()
+
+	set, err := db.OpenReplicas(&config.DBConfig{Driver: db.DriverSQLite, QueryTimeout: time.Second},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	require.Zero(t, set.Len())
+
+	for _, conn := range replicas {
+		set.Add(conn)
+	}
+	set.Check(context.Background())
+	return set
+}
+
+func TestReplicaSet(t *testing.T) {
+	primary := newNotesDB(t)
+
+	t.Run("should read from the primary outside a session", func(t *testing.T) {
+		set := newReplicaSet(t, newNotesDB(t))
+
+		assert.Same(t, primary, db.ReaderFrom(context.Background(), primary))
+		assert.Equal(t, 1, set.Healthy())
+	})
+
+	t.Run("should spread the reads of a session over the replicas", func(t *testing.T) {
+		first, second := newNotesDB(t), newNotesDB(t)
+		ctx := db.WithSession(context.Background(), newReplicaSet(t, first, second))
+
+		seen := map[db.Executor]int{}
+		for range 4 {
+			seen[db.ReaderFrom(ctx, primary)]++
+		}
+
+		assert.Equal(t, map[db.Executor]int{first: 2, second: 2}, seen)
+	})
+
+	t.Run("should read from the primary once the session writes", func(t *testing.T) {
+		replica := newNotesDB(t)
+		ctx := db.WithSession(context.Background(), newReplicaSet(t, replica))
+		other := db.WithSession(context.Background(), newReplicaSet(t, replica))
+
+		db.MarkWrite(ctx)
+
+		assert.Same(t, primary, db.ReaderFrom(ctx, primary))
+		assert.Same(t, replica, db.ReaderFrom(other, primary))
+	})
+
+	t.Run("should read from the primary when asked to", func(t *testing.T) {
+		replica := newNotesDB(t)
+		ctx := db.WithSession(context.Background(), newReplicaSet(t, replica))
+
+		assert.Same(t, primary, db.ReaderFrom(db.Primary(ctx), primary))
+		assert.Same(t, replica, db.ReaderFrom(ctx, primary), "the rest of the session still reads from the replica")
+	})
+
+	t.Run("should read inside a transaction from the transaction", func(t *testing.T) {
+		replica := newNotesDB(t)
+		ctx := db.WithSession(context.Background(), newReplicaSet(t, replica))
+
+		require.NoError(t, db.NewTransactor(primary).WithinTx(ctx, nil, func(txCtx context.Context) error {
+			assert.IsType(t, &sqlx.Tx{}, db.ReaderFrom(txCtx, primary))
+			return nil
+		}))
+		assert.Same(t, primary, db.ReaderFrom(ctx, primary), "a transaction counts as a write")
+	})
+
+	t.Run("should skip unhealthy replicas and fall back to the primary", func(t *testing.T) {
+		down, up := newNotesDB(t), newNotesDB(t)
+		set := newReplicaSet(t, down, up)
+		ctx := db.WithSession(context.Background(), set)
+
+		require.NoError(t, down.Close())
+		set.Check(context.Background())
+		assert.Equal(t, 1, set.Healthy())
+		for range 3 {
+			assert.Same(t, up, db.ReaderFrom(ctx, primary))
+		}
+
+		require.NoError(t, up.Close())
+		set.Check(context.Background())
+		assert.Zero(t, set.Healthy())
+		assert.Same(t, primary, db.ReaderFrom(ctx, primary))
+	})
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/marcelofabianov/fault"
+	_ "modernc.org/sqlite"
+
+	"github.com/marcelofabianov/dojo-go/config"
+)
+
+// SQLiteDSN opens the file at cfg.Path with foreign keys on, WAL so readers
+// do not block the writer, and immediate transactions so concurrent writers
+// wait on busy_timeout instead of failing when they upgrade their lock.
+// Times are written in SQLite's own format, which sorts as text when UTC.
+func SQLiteDSN(cfg *config.DBConfig) string {
+	query := url.Values{}
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", "journal_mode(WAL)")
+	query.Add("_pragma", "busy_timeout(5000)")
+	query.Set("_txlock", "immediate")
+	query.Set("_time_format", "sqlite")
+
+	return "file:" + cfg.Path + "?" + query.Encode()
+}
+
+func NewSQLiteConnection(cfg *config.DBConfig, logger *slog.Logger) (*sqlx.DB, error) {
+	db, err := sqlx.Open("sqlite", SQLiteDSN(cfg))
+	if err != nil {
+		return nil, fault.Wrap(err,
+			"failed to open database connection",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("path", cfg.Path),
+		)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.QueryTimeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fault.Wrap(err,
+			"failed to ping database",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("path", cfg.Path),
+		)
+	}
+
+	logger.Info("sqlite database opened successfully", "path", cfg.Path)
+
+	return db, nil
+}
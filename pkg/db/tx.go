@@ -0,0 +1,209 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/marcelofabianov/fault"
+)
+
+const (
+	// txMaxRetries is how many times a transaction that failed to serialize
+	// runs again before its error is returned.
+	txMaxRetries = 3
+	txMinBackoff = 20 * time.Millisecond
+	txMaxBackoff = 500 * time.Millisecond
+
+	serializationFailure = "40001"
+)
+
+// Executor is the subset of sqlx shared by *sqlx.DB and *sqlx.Tx, so
+// repositories can run the same queries inside and outside a transaction.
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error)
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+type txCtxKey struct{}
+
+// sqlTx is the transaction bound to a context. savepoints numbers the
+// savepoints of nested calls so their names never clash.
+type sqlTx struct {
+	*sqlx.Tx
+	savepoints int
+	hooks      commitHooks
+}
+
+// commitHooks holds the functions registered with AfterCommit. A nested call
+// that rolls back drops the functions it registered.
+type commitHooks []func()
+
+func (h *commitHooks) discard(mark int) {
+	*h = (*h)[:mark]
+}
+
+func (h commitHooks) run() {
+	for _, fn := range h {
+		fn()
+	}
+}
+
+// ExecutorFrom returns the transaction bound to ctx by Transactor.WithinTx,
+// or db when ctx carries none.
+func ExecutorFrom(ctx context.Context, db *sqlx.DB) Executor {
+	if tx, ok := ctx.Value(txCtxKey{}).(*sqlTx); ok {
+		return tx.Tx
+	}
+	return db
+}
+
+type Transactor struct {
+	db *sqlx.DB
+}
+
+func NewTransactor(db *sqlx.DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+// WithinTx runs fn in a transaction that repositories pick up from the
+// context it receives, begun with opts (nil for the defaults of the
+// database). The transaction is committed when fn returns nil and rolled back
+// when it returns an error or panics. A transaction that fails with a
+// serialization failure (SQLSTATE 40001) runs again, with backoff, up to
+// txMaxRetries times, so fn must be safe to repeat. Postgres only reports
+// them at REPEATABLE READ and above: pass sql.LevelSerializable for work that
+// reads and then writes based on what it read.
+//
+// Calls nested in an existing transaction run in a savepoint with the
+// options of the outer one: an error or panic undoes only the writes of the
+// nested fn and the outer transaction goes on. SQLite ignores the isolation
+// level, its transactions are always serializable.
+func (t *Transactor) WithinTx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	if tx, ok := ctx.Value(txCtxKey{}).(*sqlTx); ok {
+		return tx.withinSavepoint(ctx, fn)
+	}
+
+	backoff := txMinBackoff
+	for retry := 0; ; retry++ {
+		err := t.run(ctx, opts, fn)
+		if retry == txMaxRetries || !isSerializationFailure(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff/2 + rand.N(backoff/2)):
+		}
+		backoff = min(backoff*2, txMaxBackoff)
+	}
+}
+
+func (t *Transactor) run(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	MarkWrite(ctx)
+
+	tx, err := t.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return fault.Wrap(err,
+			"failed to begin transaction",
+			fault.WithCode(fault.Internal),
+		)
+	}
+	// Deferred calls also run while fn panics, so the transaction is rolled
+	// back before the panic goes on. Rollback is a no-op after Commit.
+	defer tx.Rollback()
+
+	bound := &sqlTx{Tx: tx}
+	if err := fn(context.WithValue(ctx, txCtxKey{}, bound)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fault.Wrap(err,
+			"failed to commit transaction",
+			fault.WithCode(fault.Internal),
+		)
+	}
+	bound.hooks.run()
+
+	return nil
+}
+
+// AfterCommit runs fn once the outermost transaction bound to ctx commits,
+// and never if it rolls back; outside a transaction fn runs at once. Use it
+// for side effects that must only follow committed writes, such as metrics.
+func (t *Transactor) AfterCommit(ctx context.Context, fn func()) {
+	if tx, ok := ctx.Value(txCtxKey{}).(*sqlTx); ok {
+		tx.hooks = append(tx.hooks, fn)
+		return
+	}
+	fn()
+}
+
+func (tx *sqlTx) withinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx.savepoints++
+	name := fmt.Sprintf("sp_%d", tx.savepoints)
+	mark := len(tx.hooks)
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fault.Wrap(err,
+			"failed to create savepoint",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("savepoint", name),
+		)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.rollbackTo(ctx, name)
+			tx.hooks.discard(mark)
+			panic(p)
+		}
+	}()
+
+	if err := fn(ctx); err != nil {
+		tx.hooks.discard(mark)
+		if rollbackErr := tx.rollbackTo(ctx, name); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fault.Wrap(err,
+			"failed to release savepoint",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("savepoint", name),
+		)
+	}
+
+	return nil
+}
+
+// rollbackTo undoes the writes made since the savepoint. It ignores the
+// cancellation of ctx, which is often why fn failed.
+func (tx *sqlTx) rollbackTo(ctx context.Context, name string) error {
+	if _, err := tx.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO SAVEPOINT "+name); err != nil {
+		return fault.Wrap(err,
+			"failed to roll back to savepoint",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("savepoint", name),
+		)
+	}
+	return nil
+}
+
+// isSerializationFailure reports whether Postgres aborted the transaction
+// because it could not be serialized with a concurrent one.
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == serializationFailure
+}
@@ -0,0 +1,124 @@
+//go:build integration
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+)
+
+var testDB *sqlx.DB
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	pgContainer, err := postgres.RunContainer(ctx,
+		testcontainers.WithImage("postgres:16-alpine"),
+		postgres.WithDatabase("test-db"),
+		postgres.WithUsername("user"),
+		postgres.WithPassword("password"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(5*time.Second),
+		),
+	)
+	if err != nil {
+		log.Fatalf("could not start postgres container: %s", err)
+	}
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		log.Fatalf("could not get connection string: %s", err)
+	}
+
+	testDB, err = sqlx.Connect("pgx", connStr)
+	if err != nil {
+		log.Fatalf("could not connect to database: %s", err)
+	}
+
+	exitCode := m.Run()
+
+	testDB.Close()
+	if err := pgContainer.Terminate(ctx); err != nil {
+		log.Printf("could not stop postgres container: %s", err)
+	}
+	os.Exit(exitCode)
+}
+
+func TestTransactor_WithinTx_Postgres(t *testing.T) {
+	ctx := context.Background()
+
+	// skew has two transactions each count the shifts and then add one, the
+	// second one committing while the first is still open: a write skew. It
+	// returns how many times the first one ran.
+	skew := func(t *testing.T, opts *sql.TxOptions) int {
+		t.Helper()
+
+		_, err := testDB.Exec(`DROP TABLE IF EXISTS shifts`)
+		require.NoError(t, err)
+		_, err = testDB.Exec(`CREATE TABLE shifts (doctor TEXT NOT NULL)`)
+		require.NoError(t, err)
+
+		transactor := db.NewTransactor(testDB)
+		addShift := func(ctx context.Context, doctor string) error {
+			_, err := db.ExecutorFrom(ctx, testDB).ExecContext(ctx, `INSERT INTO shifts (doctor) VALUES ($1)`, doctor)
+			return err
+		}
+
+		attempts := 0
+		err = transactor.WithinTx(ctx, opts, func(ctx context.Context) error {
+			attempts++
+
+			var count int
+			if err := db.ExecutorFrom(ctx, testDB).GetContext(ctx, &count, `SELECT COUNT(*) FROM shifts`); err != nil {
+				return err
+			}
+
+			if attempts == 1 {
+				// A fresh context is not bound to this transaction, so the
+				// concurrent one runs on another connection.
+				require.NoError(t, transactor.WithinTx(context.Background(), opts, func(ctx context.Context) error {
+					var count int
+					if err := db.ExecutorFrom(ctx, testDB).GetContext(ctx, &count, `SELECT COUNT(*) FROM shifts`); err != nil {
+						return err
+					}
+					return addShift(ctx, "bob")
+				}))
+			}
+
+			return addShift(ctx, "alice")
+		})
+		require.NoError(t, err)
+
+		return attempts
+	}
+
+	t.Run("should retry a real serialization failure at serializable", func(t *testing.T) {
+		attempts := skew(t, &sql.TxOptions{Isolation: sql.LevelSerializable})
+
+		assert.Equal(t, 2, attempts)
+
+		var count int
+		require.NoError(t, testDB.Get(&count, `SELECT COUNT(*) FROM shifts`))
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("should not detect the conflict at the default isolation level", func(t *testing.T) {
+		assert.Equal(t, 1, skew(t, nil))
+	})
+}
@@ -0,0 +1,255 @@
+//go:build unit
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+)
+
+var errFailed = errors.New("failed")
+
+func newNotesDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	conn, err := db.NewSQLiteConnection(&config.DBConfig{
+		Path:         filepath.Join(t.TempDir(), "tx.db"),
+		MaxOpenConns: 4,
+		QueryTimeout: time.Second,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	_, err = conn.Exec(`CREATE TABLE notes (body TEXT NOT NULL)`)
+	require.NoError(t, err)
+	return conn
+}
+
+func addNote(ctx context.Context, conn *sqlx.DB, body string) error {
+	_, err := db.ExecutorFrom(ctx, conn).ExecContext(ctx, `INSERT INTO notes (body) VALUES ($1)`, body)
+	return err
+}
+
+func notes(t *testing.T, conn *sqlx.DB) []string {
+	t.Helper()
+
+	bodies := []string{}
+	require.NoError(t, conn.Select(&bodies, `SELECT body FROM notes ORDER BY rowid`))
+	return bodies
+}
+
+func TestTransactor(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should commit when fn succeeds and roll back when it fails", func(t *testing.T) {
+		conn := newNotesDB(t)
+		transactor := db.NewTransactor(conn)
+
+		require.NoError(t, transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+			return addNote(ctx, conn, "kept")
+		}))
+		err := transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+			require.NoError(t, addNote(ctx, conn, "dropped"))
+			return errFailed
+		})
+
+		assert.ErrorIs(t, err, errFailed)
+		assert.Equal(t, []string{"kept"}, notes(t, conn))
+	})
+
+	t.Run("should roll back when fn panics", func(t *testing.T) {
+		conn := newNotesDB(t)
+		transactor := db.NewTransactor(conn)
+
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+				require.NoError(t, addNote(ctx, conn, "dropped"))
+				panic("boom")
+			})
+		})
+
+		assert.Empty(t, notes(t, conn))
+	})
+
+	t.Run("should undo only the failed nested call", func(t *testing.T) {
+		conn := newNotesDB(t)
+		transactor := db.NewTransactor(conn)
+
+		err := transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+			require.NoError(t, addNote(ctx, conn, "outer"))
+
+			err := transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+				require.NoError(t, addNote(ctx, conn, "nested"))
+				return errFailed
+			})
+			require.ErrorIs(t, err, errFailed)
+
+			assert.Panics(t, func() {
+				_ = transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+					require.NoError(t, addNote(ctx, conn, "panicked"))
+					panic("boom")
+				})
+			})
+
+			return transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+				return addNote(ctx, conn, "released")
+			})
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"outer", "released"}, notes(t, conn))
+	})
+
+	t.Run("should run after-commit functions of committed work only", func(t *testing.T) {
+		transactor := db.NewTransactor(newNotesDB(t))
+		checkAfterCommit(t, transactor)
+	})
+
+	t.Run("should retry serialization failures", func(t *testing.T) {
+		conn := newNotesDB(t)
+		transactor := db.NewTransactor(conn)
+
+		attempts := 0
+		err := transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+			attempts++
+			require.NoError(t, addNote(ctx, conn, "once"))
+			if attempts < 3 {
+				return &pgconn.PgError{Code: "40001"}
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, []string{"once"}, notes(t, conn))
+	})
+
+	t.Run("should give up after the last retry", func(t *testing.T) {
+		transactor := db.NewTransactor(newNotesDB(t))
+
+		attempts := 0
+		err := transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+			attempts++
+			return &pgconn.PgError{Code: "40001"}
+		})
+
+		var pgErr *pgconn.PgError
+		require.ErrorAs(t, err, &pgErr)
+		assert.Equal(t, 4, attempts)
+	})
+
+	t.Run("should not retry other errors", func(t *testing.T) {
+		transactor := db.NewTransactor(newNotesDB(t))
+
+		attempts := 0
+		err := transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+			attempts++
+			return &pgconn.PgError{Code: "23505"}
+		})
+
+		require.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+// checkAfterCommit runs the AfterCommit checks shared by every transactor.
+func checkAfterCommit(t *testing.T, transactor interface {
+	WithinTx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error
+	AfterCommit(ctx context.Context, fn func())
+}) {
+	t.Helper()
+	ctx := context.Background()
+
+	var ran []string
+	after := func(ctx context.Context, name string) {
+		transactor.AfterCommit(ctx, func() { ran = append(ran, name) })
+	}
+
+	after(ctx, "no tx")
+	assert.Equal(t, []string{"no tx"}, ran, "outside a transaction fn runs at once")
+
+	err := transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+		after(ctx, "outer")
+		_ = transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+			after(ctx, "failed nested")
+			return errFailed
+		})
+		require.NoError(t, transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+			after(ctx, "nested")
+			return nil
+		}))
+		assert.Equal(t, []string{"no tx"}, ran, "nothing runs before the outermost commit")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"no tx", "outer", "nested"}, ran)
+
+	err = transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+		require.NoError(t, transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+			after(ctx, "rolled back with the outer tx")
+			return nil
+		}))
+		return errFailed
+	})
+	require.ErrorIs(t, err, errFailed)
+	assert.Equal(t, []string{"no tx", "outer", "nested"}, ran)
+}
+
+func TestMemoryTransactor(t *testing.T) {
+	ctx := context.Background()
+	transactor := db.NewMemoryTransactor()
+
+	var log []string
+	write := func(ctx context.Context, entry string) {
+		log = append(log, entry)
+		db.OnRollback(ctx, func() { log = log[:len(log)-1] })
+	}
+
+	err := transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+		write(ctx, "outer")
+
+		err := transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+			write(ctx, "nested")
+			return errFailed
+		})
+		require.ErrorIs(t, err, errFailed)
+
+		assert.Panics(t, func() {
+			_ = transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+				write(ctx, "panicked")
+				panic("boom")
+			})
+		})
+
+		write(ctx, "last")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer", "last"}, log)
+
+	assert.Panics(t, func() {
+		_ = transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+			write(ctx, "dropped")
+			panic("boom")
+		})
+	})
+	assert.Equal(t, []string{"outer", "last"}, log)
+
+	t.Run("should run after-commit functions of committed work only", func(t *testing.T) {
+		checkAfterCommit(t, db.NewMemoryTransactor())
+	})
+}
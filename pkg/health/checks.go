@@ -0,0 +1,34 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// DBPing checks that a connection to the database can be used.
+func DBPing(db *sql.DB) Checker {
+	return db.PingContext
+}
+
+// MigrationVersion checks that the goose migrations were applied up to at
+// least minVersion. With minVersion 0 any applied migration is enough. It
+// only reads goose_db_version, it never creates it.
+func MigrationVersion(db *sql.DB, minVersion int64) Checker {
+	return func(ctx context.Context) error {
+		var version int64
+		err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version_id), 0) FROM goose_db_version WHERE is_applied`).Scan(&version)
+		if err != nil {
+			return fault.Wrap(err, "failed to read migration version", fault.WithCode(fault.InfraError))
+		}
+
+		if version == 0 || version < minVersion {
+			return fault.New(fmt.Sprintf("database is at migration %d, want at least %d", version, max(minVersion, 1)),
+				fault.WithCode(fault.InfraError),
+			)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,30 @@
+//go:build linux || darwin
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+
+	"github.com/marcelofabianov/fault"
+)
+
+// DiskSpace checks that the filesystem holding path has at least minFree
+// bytes available to unprivileged users.
+func DiskSpace(path string, minFree uint64) Checker {
+	return func(context.Context) error {
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(path, &stat); err != nil {
+			return fault.Wrap(err, "failed to stat filesystem of "+path, fault.WithCode(fault.InfraError))
+		}
+
+		free := uint64(stat.Bavail) * uint64(stat.Bsize)
+		if free < minFree {
+			return fault.New(fmt.Sprintf("only %d bytes free on %s, want at least %d", free, path, minFree),
+				fault.WithCode(fault.InfraError),
+			)
+		}
+		return nil
+	}
+}
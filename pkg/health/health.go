@@ -0,0 +1,171 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+const (
+	StatusOK   = "OK"
+	StatusFail = "FAIL"
+)
+
+// Checker reports whether a dependency is usable. It must honor ctx, which
+// carries the check timeout.
+type Checker func(ctx context.Context) error
+
+type Result struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+type check struct {
+	name    string
+	fn      Checker
+	mu      sync.Mutex
+	result  Result
+	checked time.Time
+}
+
+// Registry holds the liveness and readiness checks registered by components.
+// Results are cached for the configured TTL, so frequent probes do not turn
+// into a query per probe.
+type Registry struct {
+	mu        sync.RWMutex
+	liveness  []*check
+	readiness []*check
+	timeout   time.Duration
+	cacheTTL  time.Duration
+	draining  atomic.Bool
+}
+
+func NewRegistry(cfg *config.ServerConfig) *Registry {
+	return &Registry{
+		timeout:  cfg.Health.CheckTimeout,
+		cacheTTL: cfg.Health.CacheTTL,
+	}
+}
+
+// AddLiveness registers a check that tells whether the process must be
+// restarted. Keep these cheap and free of external dependencies.
+func (r *Registry) AddLiveness(name string, fn Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.liveness = append(r.liveness, &check{name: name, fn: fn})
+}
+
+// AddReadiness registers a check that tells whether the process can serve
+// traffic, such as a database ping.
+func (r *Registry) AddReadiness(name string, fn Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.readiness = append(r.readiness, &check{name: name, fn: fn})
+}
+
+// Drain makes readiness fail from now on, so load balancers stop sending
+// traffic while the server shuts down. Liveness is not affected.
+func (r *Registry) Drain() {
+	r.draining.Store(true)
+}
+
+func (r *Registry) Liveness(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := r.liveness
+	r.mu.RUnlock()
+
+	return r.run(ctx, checks)
+}
+
+func (r *Registry) Readiness(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := r.readiness
+	r.mu.RUnlock()
+
+	report := r.run(ctx, checks)
+	if r.draining.Load() {
+		report.Status = StatusFail
+		report.Checks["shutdown"] = Result{Status: StatusFail, Latency: "0s", Error: "server is shutting down"}
+	}
+	return report
+}
+
+func (r *Registry) LiveHandler(w http.ResponseWriter, req *http.Request) {
+	writeReport(w, req, r.Liveness(req.Context()))
+}
+
+func (r *Registry) ReadyHandler(w http.ResponseWriter, req *http.Request) {
+	writeReport(w, req, r.Readiness(req.Context()))
+}
+
+func (r *Registry) run(ctx context.Context, checks []*check) Report {
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.evaluate(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+// evaluate runs c unless its last result is still fresh. Concurrent probes
+// wait for the running check and reuse its result.
+func (r *Registry) evaluate(ctx context.Context, c *check) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checked.IsZero() && time.Since(c.checked) < r.cacheTTL {
+		return c.result
+	}
+
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := c.fn(ctx)
+	result := Result{Status: StatusOK, Latency: time.Since(start).String()}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+
+	c.result = result
+	c.checked = time.Now()
+	return result
+}
+
+func writeReport(w http.ResponseWriter, r *http.Request, report Report) {
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	web.Success(w, r, status, report)
+}
@@ -0,0 +1,110 @@
+//go:build unit
+
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/health"
+)
+
+func newRegistry(cacheTTL time.Duration) *health.Registry {
+	return health.NewRegistry(&config.ServerConfig{Health: config.HealthConfig{
+		CheckTimeout: 50 * time.Millisecond,
+		CacheTTL:     cacheTTL,
+	}})
+}
+
+func probe(t *testing.T, handler http.HandlerFunc) (int, health.Report) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var report health.Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	return rec.Code, report
+}
+
+func TestRegistry(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+
+	t.Run("should report every check with its latency", func(t *testing.T) {
+		r := newRegistry(0)
+		r.AddReadiness("database", ok)
+		r.AddReadiness("disk", func(context.Context) error { return errors.New("disk full") })
+
+		code, report := probe(t, r.ReadyHandler)
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, health.StatusFail, report.Status)
+		assert.Equal(t, health.StatusOK, report.Checks["database"].Status)
+		assert.NotEmpty(t, report.Checks["database"].Latency)
+		assert.Equal(t, "disk full", report.Checks["disk"].Error)
+	})
+
+	t.Run("should fail checks that exceed the timeout", func(t *testing.T) {
+		r := newRegistry(0)
+		r.AddReadiness("database", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		code, report := probe(t, r.ReadyHandler)
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Contains(t, report.Checks["database"].Error, "deadline exceeded")
+	})
+
+	t.Run("should cache results for the ttl", func(t *testing.T) {
+		var calls atomic.Int32
+		r := newRegistry(time.Minute)
+		r.AddReadiness("database", func(context.Context) error {
+			calls.Add(1)
+			return nil
+		})
+
+		for range 3 {
+			code, _ := probe(t, r.ReadyHandler)
+			assert.Equal(t, http.StatusOK, code)
+		}
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should keep liveness and readiness apart", func(t *testing.T) {
+		r := newRegistry(0)
+		r.AddReadiness("database", func(context.Context) error { return errors.New("connection refused") })
+
+		code, report := probe(t, r.LiveHandler)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Empty(t, report.Checks)
+	})
+
+	t.Run("should fail readiness but not liveness while draining", func(t *testing.T) {
+		r := newRegistry(0)
+		r.AddReadiness("database", ok)
+		r.AddLiveness("goroutines", ok)
+
+		r.Drain()
+
+		code, report := probe(t, r.ReadyHandler)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, health.StatusFail, report.Checks["shutdown"].Status)
+
+		code, _ = probe(t, r.LiveHandler)
+		assert.Equal(t, http.StatusOK, code)
+	})
+}
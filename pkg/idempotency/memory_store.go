@@ -0,0 +1,80 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps idempotency keys in process, for the memory database
+// driver. Keys are not shared between replicas.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+func NewMemoryStore() Store {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+func (s *MemoryStore) Reserve(_ context.Context, key, fingerprint string, ttl time.Duration) (*Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rec, ok := s.records[key]; ok && rec.ExpiresAt.After(time.Now()) {
+		return cloneRecord(rec), false, nil
+	}
+
+	s.records[key] = Record{Key: key, Fingerprint: fingerprint, ExpiresAt: time.Now().Add(ttl)}
+	return nil, true, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, key string, statusCode int, header http.Header, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.records[key]
+	if !ok {
+		return nil
+	}
+
+	rec.StatusCode = statusCode
+	rec.Header = header.Clone()
+	rec.Body = slices.Clone(body)
+	rec.Completed = true
+	s.records[key] = rec
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rec, ok := s.records[key]; ok && !rec.Completed {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeleteExpired(context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var deleted int64
+	for key, rec := range s.records {
+		if !rec.ExpiresAt.After(now) {
+			delete(s.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func cloneRecord(rec Record) *Record {
+	rec.Header = rec.Header.Clone()
+	rec.Body = slices.Clone(rec.Body)
+	return &rec
+}
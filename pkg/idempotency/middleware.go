@@ -0,0 +1,252 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+	maxKeyLength   = 255
+	storeTimeout   = 5 * time.Second
+)
+
+// replayedHeaders are the response headers stored and replayed with the
+// original response.
+var replayedHeaders = []string{"Content-Type", "Location"}
+
+// Owner names the principal a request is made on behalf of, such as the
+// authenticated user or API key.
+type Owner func(ctx context.Context) string
+
+type Middleware struct {
+	store           Store
+	owner           Owner
+	ttl             time.Duration
+	cleanupInterval time.Duration
+	logger          *slog.Logger
+}
+
+func NewMiddleware(cfg *config.ServerConfig, store Store, owner Owner, logger *slog.Logger) *Middleware {
+	return &Middleware{
+		store:           store,
+		owner:           owner,
+		ttl:             cfg.Idempotency.TTL,
+		cleanupInterval: cfg.Idempotency.CleanupInterval,
+		logger:          logger,
+	}
+}
+
+// Handler makes requests carrying an Idempotency-Key safe to retry: the first
+// response is stored and replayed for retries with the same payload, retries
+// with a different payload are rejected with 422 and concurrent retries with
+// 409 while the first request is still running. Requests without the header
+// are passed through untouched.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(HeaderKey)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		logger := web.GetLogger(r.Context()).With("idempotency_key", key)
+
+		if len(key) > maxKeyLength {
+			web.Error(w, r, fault.New("idempotency key is too long",
+				fault.WithCode(fault.Invalid),
+				fault.WithContext("max_length", maxKeyLength),
+			))
+			return
+		}
+
+		key = m.scope(r.Context(), key)
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			logger.Error("failed to read request body", "error", err)
+			web.ErrDecodeRequestBody(err, w, r)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := fingerprint(r, body)
+
+		record, reserved, err := m.store.Reserve(r.Context(), key, fingerprint, m.ttl)
+		if err != nil {
+			logger.Error("failed to reserve idempotency key", "error", err)
+			web.Error(w, r, err)
+			return
+		}
+
+		if !reserved {
+			m.replay(w, r, logger, record, fingerprint)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, header: make(http.Header)}
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			// The request failed or panicked: free the key so it can be retried.
+			ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+			defer cancel()
+			if err := m.store.Release(ctx, key); err != nil {
+				logger.Error("failed to release idempotency key", "error", err)
+			}
+		}()
+
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+		defer cancel()
+
+		if rec.status >= http.StatusInternalServerError {
+			// Server errors are not final, a retry may succeed.
+			return
+		}
+
+		if err := m.store.Complete(ctx, key, rec.status, rec.header, rec.body.Bytes()); err != nil {
+			logger.Error("failed to store idempotent response", "error", err)
+			return
+		}
+		completed = true
+	})
+}
+
+func (m *Middleware) replay(w http.ResponseWriter, r *http.Request, logger *slog.Logger, record *Record, fingerprint string) {
+	if record.Fingerprint != fingerprint {
+		logger.Warn("idempotency key reused with a different payload")
+		web.Error(w, r, fault.New("idempotency key was already used with a different request payload",
+			fault.WithCode(fault.DomainViolation),
+		))
+		return
+	}
+
+	if !record.Completed {
+		logger.Warn("idempotency key is still being processed")
+		web.Error(w, r, fault.New("a request with this idempotency key is still being processed",
+			fault.WithCode(fault.Conflict),
+		))
+		return
+	}
+
+	logger.Info("replaying idempotent response", "status", record.StatusCode)
+
+	for name, values := range record.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	w.Header().Set(HeaderReplayed, "true")
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.Body)
+}
+
+// RunCleanup deletes expired keys every cleanup interval until ctx is
+// canceled. Expired keys are also ignored on Reserve, so this only bounds the
+// table size. A cleanup interval of zero or less turns the cleanup off.
+func (m *Middleware) RunCleanup(ctx context.Context) {
+	if m.cleanupInterval <= 0 {
+		m.logger.Warn("idempotency key cleanup is disabled", "cleanup_interval", m.cleanupInterval)
+		return
+	}
+
+	ticker := time.NewTicker(m.cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := m.store.DeleteExpired(ctx)
+			if err != nil {
+				m.logger.Error("failed to delete expired idempotency keys", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				m.logger.Info("expired idempotency keys deleted", "count", deleted)
+			}
+		}
+	}
+}
+
+// scope namespaces a key by tenant and owner. Keys are chosen by clients, so
+// without it one client could replay the responses of another. The owner and
+// key are hashed together, which keeps them apart whatever characters they
+// contain and keeps the stored key within its column.
+func (m *Middleware) scope(ctx context.Context, key string) string {
+	h := sha256.New()
+	h.Write([]byte(m.owner(ctx)))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	scoped := hex.EncodeToString(h.Sum(nil))
+
+	if tenantID, ok := tenant.FromContext(ctx); ok {
+		return tenantID + ":" + scoped
+	}
+	return scoped
+}
+
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder writes through to the client while keeping a copy of the
+// status, replayable headers and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	if rr.status != 0 {
+		return
+	}
+	rr.status = status
+	for _, name := range replayedHeaders {
+		if values := rr.ResponseWriter.Header().Values(name); len(values) > 0 {
+			rr.header[name] = values
+		}
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.WriteHeader(http.StatusOK)
+	}
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
@@ -0,0 +1,190 @@
+//go:build unit
+
+package idempotency_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/auth"
+	"github.com/marcelofabianov/dojo-go/pkg/idempotency"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+// subject owns requests by their JWT subject.
+func subject(ctx context.Context) string {
+	if claims, ok := auth.ClaimsFromContext(ctx); ok {
+		return claims.Subject
+	}
+	return ""
+}
+
+func newMiddleware(store idempotency.Store) *idempotency.Middleware {
+	cfg := &config.ServerConfig{Idempotency: config.IdempotencyConfig{TTL: time.Hour, CleanupInterval: time.Hour}}
+	return idempotency.NewMiddleware(cfg, store, subject, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func doRequest(h http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/courses", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(idempotency.HeaderKey, key)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware_Handler(t *testing.T) {
+	t.Run("should replay the stored response for a retry with the same payload", func(t *testing.T) {
+		calls := 0
+		h := newMiddleware(idempotency.NewMemoryStore()).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}))
+
+		first := doRequest(h, "key-1", `{"title":"Go"}`)
+		second := doRequest(h, "key-1", `{"title":"Go"}`)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+		assert.Equal(t, "true", second.Header().Get(idempotency.HeaderReplayed))
+		assert.Empty(t, first.Header().Get(idempotency.HeaderReplayed))
+	})
+
+	t.Run("should reject a retry with a different payload", func(t *testing.T) {
+		h := newMiddleware(idempotency.NewMemoryStore()).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		doRequest(h, "key-1", `{"title":"Go"}`)
+		rec := doRequest(h, "key-1", `{"title":"Rust"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("should reject a concurrent retry while the first request is running", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		h := newMiddleware(idempotency.NewMemoryStore()).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- doRequest(h, "key-1", `{}`) }()
+		<-started
+
+		rec := doRequest(h, "key-1", `{}`)
+		close(release)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, http.StatusCreated, (<-done).Code)
+	})
+
+	t.Run("should release the key when the handler fails", func(t *testing.T) {
+		calls := 0
+		h := newMiddleware(idempotency.NewMemoryStore()).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		assert.Equal(t, http.StatusInternalServerError, doRequest(h, "key-1", `{}`).Code)
+		assert.Equal(t, http.StatusCreated, doRequest(h, "key-1", `{}`).Code)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should pass requests without a key through", func(t *testing.T) {
+		calls := 0
+		h := newMiddleware(idempotency.NewMemoryStore()).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		doRequest(h, "", `{}`)
+		doRequest(h, "", `{}`)
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should reject keys that are too long", func(t *testing.T) {
+		h := newMiddleware(idempotency.NewMemoryStore()).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		}))
+
+		rec := doRequest(h, strings.Repeat("k", 256), `{}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("should not replay responses across tenants", func(t *testing.T) {
+		calls := 0
+		h := newMiddleware(idempotency.NewMemoryStore()).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		for _, tenantID := range []string{"school-a", "school-b"} {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/courses", strings.NewReader(`{}`))
+			req = req.WithContext(tenant.WithID(req.Context(), tenantID))
+			req.Header.Set(idempotency.HeaderKey, "key-1")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Empty(t, rec.Header().Get(idempotency.HeaderReplayed))
+		}
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should not replay responses across principals of a tenant", func(t *testing.T) {
+		calls := 0
+		h := newMiddleware(idempotency.NewMemoryStore()).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		for _, sub := range []string{"user-1", "user-2", "user-1"} {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/courses", strings.NewReader(`{}`))
+			ctx := tenant.WithID(req.Context(), "school-a")
+			ctx = auth.WithClaims(ctx, &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: sub}})
+			req = req.WithContext(ctx)
+			req.Header.Set(idempotency.HeaderKey, "key-1")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusCreated, rec.Code)
+		}
+
+		assert.Equal(t, 2, calls, "only the retry of user-1 should be replayed")
+	})
+}
+
+func TestMiddleware_RunCleanup(t *testing.T) {
+	t.Run("should not run without a positive cleanup interval", func(t *testing.T) {
+		for _, interval := range []time.Duration{0, -time.Minute} {
+			cfg := &config.ServerConfig{Idempotency: config.IdempotencyConfig{TTL: time.Hour, CleanupInterval: interval}}
+			m := idempotency.NewMiddleware(cfg, idempotency.NewMemoryStore(), subject, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			assert.NotPanics(t, func() { m.RunCleanup(context.Background()) })
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+)
+
+// SQLiteStore is PostgresStore on the schema of db/sqlite/migrations. It
+// passes the current time in UTC instead of CURRENT_TIMESTAMP, whose format
+// does not compare as text with the stored times.
+type SQLiteStore struct {
+	db         db.Executor
+	transactor *db.Transactor
+}
+
+func NewSQLiteStore(conn *sqlx.DB, cfg *config.DBConfig) Store {
+	return &SQLiteStore{db: db.Bounded(conn, cfg), transactor: db.NewTransactor(conn)}
+}
+
+func (s *SQLiteStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (record *Record, reserved bool, err error) {
+	err = s.transactor.WithinTx(ctx, nil, func(ctx context.Context) error {
+		record, reserved, err = s.reserve(ctx, key, fingerprint, ttl)
+		return err
+	})
+	return record, reserved, err
+}
+
+// reserve runs in the transaction bound to ctx, which s.db picks up.
+func (s *SQLiteStore) reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, bool, error) {
+	now := time.Now().UTC()
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE key = $1 AND expires_at <= $2`,
+		key, now,
+	); err != nil {
+		return nil, false, fault.Wrap(err,
+			"failed to delete expired idempotency key",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (key, fingerprint, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO NOTHING
+	`, key, fingerprint, now, now.Add(ttl))
+	if err != nil {
+		return nil, false, fault.Wrap(err,
+			"failed to reserve idempotency key",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, fault.Wrap(err,
+			"failed to get rows affected after reserve",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	if inserted == 1 {
+		return nil, true, nil
+	}
+
+	var row recordRow
+	if err := s.db.GetContext(ctx, &row, `
+		SELECT key, fingerprint, status_code, response_headers, response_body, completed_at, expires_at
+		FROM idempotency_keys
+		WHERE key = $1
+	`, key); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Released by a concurrent request between the insert and the
+			// select; the client can simply retry.
+			return nil, false, fault.New("idempotency key was released, retry the request",
+				fault.WithCode(fault.Conflict),
+			)
+		}
+		return nil, false, fault.Wrap(err,
+			"failed to get idempotency key",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	record := &Record{
+		Key:         row.Key,
+		Fingerprint: row.Fingerprint,
+		StatusCode:  int(row.StatusCode.Int32),
+		Body:        row.Body,
+		Completed:   row.CompletedAt.Valid,
+		ExpiresAt:   row.ExpiresAt,
+	}
+	if len(row.Header) > 0 {
+		if err := json.Unmarshal(row.Header, &record.Header); err != nil {
+			return nil, false, fault.Wrap(err,
+				"failed to decode stored response headers",
+				fault.WithCode(fault.Internal),
+			)
+		}
+	}
+
+	return record, false, nil
+}
+
+func (s *SQLiteStore) Complete(ctx context.Context, key string, statusCode int, header http.Header, body []byte) error {
+	rawHeader, err := json.Marshal(header)
+	if err != nil {
+		return fault.Wrap(err,
+			"failed to encode response headers",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE idempotency_keys
+		SET status_code = $2, response_headers = $3, response_body = $4, completed_at = $5
+		WHERE key = $1
+	`, key, statusCode, rawHeader, body, time.Now().UTC()); err != nil {
+		return fault.Wrap(err,
+			"failed to store idempotent response",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return nil
+}
+
+func (s *SQLiteStore) Release(ctx context.Context, key string) error {
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE key = $1 AND completed_at IS NULL`,
+		key,
+	); err != nil {
+		return fault.Wrap(err,
+			"failed to release idempotency key",
+			fault.WithCode(fault.Internal),
+		)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, time.Now().UTC())
+	if err != nil {
+		return 0, fault.Wrap(err,
+			"failed to delete expired idempotency keys",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fault.Wrap(err,
+			"failed to get rows affected after cleanup",
+			fault.WithCode(fault.Internal),
+		)
+	}
+
+	return deleted, nil
+}
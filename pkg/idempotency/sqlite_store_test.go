@@ -0,0 +1,102 @@
+//go:build unit
+
+package idempotency_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/marcelofabianov/fault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
+	"github.com/marcelofabianov/dojo-go/pkg/idempotency"
+	"github.com/marcelofabianov/dojo-go/pkg/migrate"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+func newSQLiteStore(t *testing.T) (idempotency.Store, *sqlx.DB) {
+	t.Helper()
+
+	cfg := &config.DBConfig{
+		Path:         filepath.Join(t.TempDir(), "dojo.db"),
+		MaxOpenConns: 4,
+		QueryTimeout: time.Second,
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	conn, err := db.NewSQLiteConnection(cfg, logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	migrator, err := migrate.New(conn, logger)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up(context.Background()))
+
+	return idempotency.NewSQLiteStore(conn, cfg), conn
+}
+
+func TestSQLiteStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should replay the completed response", func(t *testing.T) {
+		store, _ := newSQLiteStore(t)
+
+		_, reserved, err := store.Reserve(ctx, "key-1", "fp", time.Hour)
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		rec, reserved, err := store.Reserve(ctx, "key-1", "fp", time.Hour)
+		require.NoError(t, err)
+		require.False(t, reserved)
+		assert.False(t, rec.Completed)
+
+		header := http.Header{"Content-Type": {"application/json"}}
+		require.NoError(t, store.Complete(ctx, "key-1", http.StatusCreated, header, []byte(`{"id":"1"}`)))
+		require.NoError(t, store.Release(ctx, "key-1"), "completed keys are kept")
+
+		rec, reserved, err = store.Reserve(ctx, "key-1", "fp", time.Hour)
+		require.NoError(t, err)
+		require.False(t, reserved)
+		assert.True(t, rec.Completed)
+		assert.Equal(t, http.StatusCreated, rec.StatusCode)
+		assert.Equal(t, header, rec.Header)
+		assert.Equal(t, `{"id":"1"}`, string(rec.Body))
+	})
+
+	t.Run("should free released and expired keys", func(t *testing.T) {
+		store, _ := newSQLiteStore(t)
+
+		_, _, err := store.Reserve(ctx, "released", "fp", time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, store.Release(ctx, "released"))
+
+		_, reserved, err := store.Reserve(ctx, "released", "fp", time.Hour)
+		require.NoError(t, err)
+		assert.True(t, reserved)
+
+		_, _, err = store.Reserve(ctx, "expired", "fp", -time.Second)
+		require.NoError(t, err)
+
+		deleted, err := store.DeleteExpired(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+	})
+
+	t.Run("should bound writes by the exec timeout", func(t *testing.T) {
+		_, conn := newSQLiteStore(t)
+		store := idempotency.NewSQLiteStore(conn, &config.DBConfig{ExecTimeout: time.Nanosecond})
+
+		err := store.Release(ctx, "key-1")
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, fault.IsCode(err, web.Timeout))
+	})
+}
@@ -0,0 +1,30 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type Record struct {
+	Key         string
+	Fingerprint string
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+	Completed   bool
+	ExpiresAt   time.Time
+}
+
+// Store persists idempotency keys and the responses recorded for them.
+type Store interface {
+	// Reserve claims key for a new request. When the key is already taken and
+	// not expired, it returns the existing record and false.
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, bool, error)
+	// Complete stores the response of the request that reserved key.
+	Complete(ctx context.Context, key string, statusCode int, header http.Header, body []byte) error
+	// Release drops a reservation so the request can be retried.
+	Release(ctx context.Context, key string) error
+	// DeleteExpired removes expired keys and returns how many were removed.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,69 @@
+//go:build unit
+
+package metrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/pkg/metrics"
+)
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	t.Run("should label requests with the chi route pattern and status", func(t *testing.T) {
+		m := metrics.New()
+		r := chi.NewRouter()
+		r.Use(m.Middleware)
+		r.Get("/api/v1/courses/{id}", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		for _, path := range []string{"/api/v1/courses/1", "/api/v1/courses/2", "/unknown"} {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+
+		body := scrape(t, m)
+		assert.Contains(t, body, `dojo_http_requests_total{method="GET",route="/api/v1/courses/{id}",status="404"} 2`)
+		assert.Contains(t, body, `dojo_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+		assert.Contains(t, body, `dojo_http_request_duration_seconds_count{method="GET",route="/api/v1/courses/{id}",status="404"} 2`)
+	})
+
+	t.Run("should count rate limit rejections per limiter", func(t *testing.T) {
+		m := metrics.New()
+
+		rec := httptest.NewRecorder()
+		m.LimitHandler("tenant").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Contains(t, scrape(t, m), `dojo_http_rate_limited_total{limiter="tenant"} 1`)
+	})
+
+	t.Run("should count domain events", func(t *testing.T) {
+		m := metrics.New()
+		m.CountEvent("course.created")
+		m.CountEvent("course.created")
+		m.CountEvent("course.deleted")
+
+		body := scrape(t, m)
+		assert.Contains(t, body, `dojo_domain_events_total{event="course.created"} 2`)
+		assert.Contains(t, body, `dojo_domain_events_total{event="course.deleted"} 1`)
+	})
+}
@@ -0,0 +1,155 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/marcelofabianov/fault"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+
+	migrations "github.com/marcelofabianov/dojo-go/db"
+)
+
+// Migrator applies the embedded goose migrations. On Postgres every command
+// holds a session advisory lock, so replicas starting together never apply
+// the same migration twice. SQLite connections get their own migration set.
+type Migrator struct {
+	provider *goose.Provider
+	logger   *slog.Logger
+}
+
+func New(conn *sqlx.DB, logger *slog.Logger) (*Migrator, error) {
+	if conn == nil {
+		return nil, fault.New("migrations need a database connection, the memory driver has none",
+			fault.WithCode(fault.Invalid),
+		)
+	}
+
+	provider, err := newProvider(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{provider: provider, logger: logger}, nil
+}
+
+func newProvider(conn *sqlx.DB) (*goose.Provider, error) {
+	dialect, fsys := goose.DialectPostgres, migrations.Migrations()
+	var opts []goose.ProviderOption
+
+	if conn.DriverName() == "sqlite" {
+		// A single process owns the file, so there is no lock to take.
+		dialect, fsys = goose.DialectSQLite3, migrations.SQLiteMigrations()
+	} else {
+		locker, err := lock.NewPostgresSessionLocker()
+		if err != nil {
+			return nil, fault.Wrap(err, "failed to create migration lock", fault.WithCode(fault.Internal))
+		}
+		opts = append(opts, goose.WithSessionLocker(locker))
+	}
+
+	provider, err := goose.NewProvider(dialect, conn.DB, fsys, opts...)
+	if err != nil {
+		return nil, fault.Wrap(err, "failed to load migrations", fault.WithCode(fault.Internal))
+	}
+	return provider, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	results, err := m.provider.Up(ctx)
+	m.log(results...)
+	if err != nil {
+		return fault.Wrap(err, "failed to apply migrations", fault.WithCode(fault.InfraError))
+	}
+
+	if len(results) == 0 {
+		m.logger.Info("database schema is up to date")
+	}
+	return nil
+}
+
+// Down rolls back the latest applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	result, err := m.provider.Down(ctx)
+	m.log(result)
+	if err != nil {
+		return fault.Wrap(err, "failed to roll back migration", fault.WithCode(fault.InfraError))
+	}
+	return nil
+}
+
+// Redo rolls back the latest applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) error {
+	if err := m.Down(ctx); err != nil {
+		return err
+	}
+
+	result, err := m.provider.UpByOne(ctx)
+	m.log(result)
+	if err != nil {
+		return fault.Wrap(err, "failed to reapply migration", fault.WithCode(fault.InfraError))
+	}
+	return nil
+}
+
+// To migrates up or down until version is the latest applied migration.
+func (m *Migrator) To(ctx context.Context, version int64) error {
+	current, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	var results []*goose.MigrationResult
+	switch {
+	case version > current:
+		results, err = m.provider.UpTo(ctx, version)
+	case version < current:
+		results, err = m.provider.DownTo(ctx, version)
+	default:
+		m.logger.Info("database schema is already at version", "version", version)
+		return nil
+	}
+	m.log(results...)
+	if err != nil {
+		return fault.Wrap(err, "failed to migrate to version",
+			fault.WithCode(fault.InfraError),
+			fault.WithContext("version", version),
+		)
+	}
+	return nil
+}
+
+// Status reports every known migration and whether it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, fault.Wrap(err, "failed to read migration status", fault.WithCode(fault.InfraError))
+	}
+	return statuses, nil
+}
+
+// Version returns the latest applied migration, or 0 on an empty database.
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	version, err := m.provider.GetDBVersion(ctx)
+	if err != nil && !errors.Is(err, goose.ErrNoCurrentVersion) {
+		return 0, fault.Wrap(err, "failed to read database version", fault.WithCode(fault.InfraError))
+	}
+	return version, nil
+}
+
+func (m *Migrator) log(results ...*goose.MigrationResult) {
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		if r.Error != nil {
+			m.logger.Error("migration failed", "version", r.Source.Version, "direction", r.Direction, "error", r.Error)
+			continue
+		}
+		m.logger.Info("migration applied", "version", r.Source.Version, "direction", r.Direction, "duration", r.Duration)
+	}
+}
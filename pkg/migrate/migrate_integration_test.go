@@ -0,0 +1,135 @@
+//go:build integration
+
+package migrate_test
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/marcelofabianov/dojo-go/pkg/migrate"
+)
+
+var testDB *sqlx.DB
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	pgContainer, err := postgres.RunContainer(ctx,
+		testcontainers.WithImage("postgres:16-alpine"),
+		postgres.WithDatabase("test-db"),
+		postgres.WithUsername("user"),
+		postgres.WithPassword("password"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(5*time.Second),
+		),
+	)
+	if err != nil {
+		log.Fatalf("could not start postgres container: %s", err)
+	}
+	defer func() {
+		if err := pgContainer.Terminate(ctx); err != nil {
+			log.Fatalf("could not stop postgres container: %s", err)
+		}
+	}()
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		log.Fatalf("could not get connection string: %s", err)
+	}
+
+	testDB, err = sqlx.Connect("pgx", connStr)
+	if err != nil {
+		log.Fatalf("could not connect to database: %s", err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func newMigrator(t *testing.T) *migrate.Migrator {
+	t.Helper()
+
+	m, err := migrate.New(testDB, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	return m
+}
+
+func TestMigrator_Integration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should let concurrent replicas apply migrations only once", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make([]error, 3)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = newMigrator(t).Up(ctx)
+			}()
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+
+		statuses, err := newMigrator(t).Status(ctx)
+		require.NoError(t, err)
+		for _, s := range statuses {
+			assert.Equal(t, goose.StateApplied, s.State, "migration %d", s.Source.Version)
+		}
+
+		var applied int
+		require.NoError(t, testDB.Get(&applied, `SELECT COUNT(*) FROM goose_db_version WHERE version_id > 0`))
+		assert.Equal(t, len(statuses), applied)
+	})
+
+	t.Run("should migrate down and back up to a version", func(t *testing.T) {
+		m := newMigrator(t)
+
+		statuses, err := m.Status(ctx)
+		require.NoError(t, err)
+		require.Greater(t, len(statuses), 1)
+		first := statuses[0].Source.Version
+		latest := statuses[len(statuses)-1].Source.Version
+
+		require.NoError(t, m.To(ctx, first))
+		version, err := m.Version(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, first, version)
+
+		require.NoError(t, m.To(ctx, latest))
+		version, err = m.Version(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, latest, version)
+	})
+
+	t.Run("should redo the latest migration", func(t *testing.T) {
+		m := newMigrator(t)
+
+		before, err := m.Version(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, m.Redo(ctx))
+
+		after, err := m.Version(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+}
@@ -0,0 +1,240 @@
+package shutdown
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+)
+
+// inFlightPollInterval is how often Shutdown checks whether the last
+// in-flight request has finished, like http.Server.Shutdown does for idle
+// connections.
+const inFlightPollInterval = 20 * time.Millisecond
+
+// Drainer is told to fail readiness probes when shutdown starts.
+type Drainer interface {
+	Drain()
+}
+
+// Server stops accepting new work and waits for the work it accepted, such
+// as *http.Server.
+type Server interface {
+	Shutdown(ctx context.Context) error
+}
+
+type ServerFunc func(ctx context.Context) error
+
+func (f ServerFunc) Shutdown(ctx context.Context) error { return f(ctx) }
+
+type namedServer struct {
+	name   string
+	server Server
+}
+
+type namedCloser struct {
+	name  string
+	close func() error
+}
+
+// Coordinator runs the shutdown sequence in a fixed order: fail readiness,
+// wait the drain delay, stop the servers, wait for in-flight requests, stop
+// background workers and finally close resources such as the database pool.
+type Coordinator struct {
+	logger     *slog.Logger
+	drainer    Drainer
+	drainDelay time.Duration
+	timeout    time.Duration
+
+	mu      sync.Mutex
+	servers []namedServer
+	closers []namedCloser
+
+	inFlight atomic.Int64
+	stopping chan struct{}
+
+	workersCtx    context.Context
+	cancelWorkers context.CancelFunc
+	workers       sync.WaitGroup
+}
+
+func NewCoordinator(cfg *config.ServerConfig, logger *slog.Logger, drainer Drainer) *Coordinator {
+	workersCtx, cancel := context.WithCancel(context.Background())
+	return &Coordinator{
+		logger:        logger,
+		drainer:       drainer,
+		drainDelay:    cfg.Shutdown.DrainDelay,
+		timeout:       cfg.Shutdown.Timeout,
+		stopping:      make(chan struct{}),
+		workersCtx:    workersCtx,
+		cancelWorkers: cancel,
+	}
+}
+
+// AddServer registers a server to be stopped after the drain delay. Servers
+// are stopped in registration order.
+func (c *Coordinator) AddServer(name string, server Server) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.servers = append(c.servers, namedServer{name: name, server: server})
+}
+
+// AddCloser registers a resource to be closed once servers and workers are
+// done. Closers run in registration order.
+func (c *Coordinator) AddCloser(name string, close func() error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closers = append(c.closers, namedCloser{name: name, close: close})
+}
+
+// Go runs a background worker until shutdown cancels its context, after
+// every server has stopped.
+func (c *Coordinator) Go(name string, run func(ctx context.Context)) {
+	c.workers.Add(1)
+	go func() {
+		defer c.workers.Done()
+		run(c.workersCtx)
+		c.logger.Info("background worker stopped", "worker", name)
+	}()
+}
+
+type stoppingCtxKey struct{}
+
+// Stopping returns a channel closed when the servers start shutting down.
+// Long-lived streams (SSE, WebSocket) select on it to end themselves, since
+// http.Server.Shutdown does not interrupt active requests. Requests that did
+// not go through Middleware get a nil channel, which never fires.
+func Stopping(ctx context.Context) <-chan struct{} {
+	stopping, _ := ctx.Value(stoppingCtxKey{}).(<-chan struct{})
+	return stopping
+}
+
+// Middleware counts in-flight requests, including hijacked connections that
+// http.Server.Shutdown no longer tracks, and exposes Stopping to handlers.
+func (c *Coordinator) Middleware(next http.Handler) http.Handler {
+	var stopping <-chan struct{} = c.stopping
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.inFlight.Add(1)
+		defer c.inFlight.Add(-1)
+
+		ctx := context.WithValue(r.Context(), stoppingCtxKey{}, stopping)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (c *Coordinator) InFlight() int64 {
+	return c.inFlight.Load()
+}
+
+// Shutdown runs the whole sequence within the configured timeout. Every
+// phase runs even if an earlier one failed, so resources are always
+// released; the first error is returned.
+func (c *Coordinator) Shutdown(ctx context.Context) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	c.mu.Lock()
+	servers := c.servers
+	closers := c.closers
+	c.mu.Unlock()
+
+	start := time.Now()
+	var firstErr error
+	phase := func(name string, fn func() error) {
+		phaseStart := time.Now()
+		c.logger.Info("shutdown phase started", "phase", name)
+		if err := fn(); err != nil {
+			c.logger.Error("shutdown phase failed", "phase", name, "duration", time.Since(phaseStart), "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			return
+		}
+		c.logger.Info("shutdown phase completed", "phase", name, "duration", time.Since(phaseStart))
+	}
+
+	phase("mark not ready", func() error {
+		if c.drainer != nil {
+			c.drainer.Drain()
+		}
+		return nil
+	})
+
+	phase("drain delay", func() error {
+		select {
+		case <-time.After(c.drainDelay):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	phase("stop servers", func() error {
+		close(c.stopping)
+
+		var err error
+		for _, s := range servers {
+			if serr := s.server.Shutdown(ctx); serr != nil && err == nil {
+				err = fault.Wrap(serr, "failed to stop "+s.name, fault.WithCode(fault.Internal))
+			}
+		}
+		return err
+	})
+
+	phase("wait in-flight requests", func() error {
+		c.logger.Info("waiting for in-flight requests", "in_flight", c.InFlight())
+
+		ticker := time.NewTicker(inFlightPollInterval)
+		defer ticker.Stop()
+		for c.InFlight() > 0 {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return fault.Wrap(ctx.Err(), "in-flight requests did not finish in time",
+					fault.WithCode(fault.Internal),
+					fault.WithContext("in_flight", c.InFlight()),
+				)
+			}
+		}
+		return nil
+	})
+
+	phase("stop background workers", func() error {
+		c.cancelWorkers()
+
+		done := make(chan struct{})
+		go func() {
+			c.workers.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return fault.Wrap(ctx.Err(), "background workers did not stop in time", fault.WithCode(fault.Internal))
+		}
+	})
+
+	phase("close resources", func() error {
+		var err error
+		for _, cl := range closers {
+			if cerr := cl.close(); cerr != nil && err == nil {
+				err = fault.Wrap(cerr, "failed to close "+cl.name, fault.WithCode(fault.Internal))
+			}
+		}
+		return err
+	})
+
+	c.logger.Info("shutdown completed", "duration", time.Since(start))
+	return firstErr
+}
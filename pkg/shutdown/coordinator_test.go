@@ -0,0 +1,183 @@
+//go:build unit
+
+package shutdown_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/shutdown"
+)
+
+// recorder keeps the order in which the shutdown steps happened.
+type recorder struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (r *recorder) add(step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, step)
+}
+
+func (r *recorder) Drain() { r.add("drain") }
+
+func (r *recorder) Steps() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.steps...)
+}
+
+func newCoordinator(drainer shutdown.Drainer, drainDelay, timeout time.Duration) *shutdown.Coordinator {
+	cfg := &config.ServerConfig{Shutdown: config.ShutdownConfig{DrainDelay: drainDelay, Timeout: timeout}}
+	return shutdown.NewCoordinator(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), drainer)
+}
+
+// serve starts handler behind the coordinator on a random port.
+func serve(t *testing.T, c *shutdown.Coordinator, handler http.HandlerFunc) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := &http.Server{Handler: c.Middleware(handler)}
+	c.AddServer("http server", srv)
+	go srv.Serve(lis)
+
+	return "http://" + lis.Addr().String()
+}
+
+func TestCoordinator(t *testing.T) {
+	t.Run("should let in-flight requests finish before closing resources", func(t *testing.T) {
+		rec := &recorder{}
+		c := newCoordinator(rec, 50*time.Millisecond, 5*time.Second)
+
+		started := make(chan struct{})
+		url := serve(t, c, func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			time.Sleep(300 * time.Millisecond)
+			rec.add("request finished")
+			w.Write([]byte("done"))
+		})
+
+		c.Go("worker", func(ctx context.Context) {
+			<-ctx.Done()
+			rec.add("worker stopped")
+		})
+		c.AddCloser("database pool", func() error {
+			rec.add("database closed")
+			return nil
+		})
+
+		type result struct {
+			body string
+			err  error
+		}
+		responses := make(chan result, 1)
+		go func() {
+			resp, err := http.Get(url)
+			if err != nil {
+				responses <- result{err: err}
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			responses <- result{body: string(body), err: err}
+		}()
+
+		<-started
+		assert.Equal(t, int64(1), c.InFlight())
+
+		require.NoError(t, c.Shutdown(context.Background()))
+
+		res := <-responses
+		require.NoError(t, res.err)
+		assert.Equal(t, "done", res.body)
+		assert.Equal(t, int64(0), c.InFlight())
+		assert.Equal(t, []string{"drain", "request finished", "worker stopped", "database closed"}, rec.Steps())
+
+		_, err := http.Get(url)
+		assert.Error(t, err, "new connections must be refused after shutdown")
+	})
+
+	t.Run("should keep serving during the drain delay", func(t *testing.T) {
+		c := newCoordinator(&recorder{}, 200*time.Millisecond, 5*time.Second)
+		url := serve(t, c, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		done := make(chan error, 1)
+		go func() { done <- c.Shutdown(context.Background()) }()
+
+		time.Sleep(50 * time.Millisecond)
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		require.NoError(t, <-done)
+	})
+
+	t.Run("should end streams through Stopping", func(t *testing.T) {
+		c := newCoordinator(&recorder{}, 0, 5*time.Second)
+
+		streaming := make(chan struct{})
+		url := serve(t, c, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			http.NewResponseController(w).Flush()
+			close(streaming)
+
+			select {
+			case <-shutdown.Stopping(r.Context()):
+			case <-time.After(5 * time.Second):
+				t.Error("stream was not told to stop")
+			}
+		})
+
+		go func() {
+			if resp, err := http.Get(url); err == nil {
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+		}()
+
+		<-streaming
+		start := time.Now()
+		require.NoError(t, c.Shutdown(context.Background()))
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("should give up on requests that outlive the timeout", func(t *testing.T) {
+		c := newCoordinator(&recorder{}, 0, 100*time.Millisecond)
+
+		closed := false
+		c.AddCloser("database pool", func() error {
+			closed = true
+			return nil
+		})
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		url := serve(t, c, func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		})
+		go http.Get(url)
+
+		<-started
+		assert.Error(t, c.Shutdown(context.Background()))
+		assert.True(t, closed, "resources must be released even when the deadline is exceeded")
+	})
+}
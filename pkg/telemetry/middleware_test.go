@@ -0,0 +1,72 @@
+//go:build unit
+
+package telemetry_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/marcelofabianov/dojo-go/pkg/telemetry"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+func TestMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	r := chi.NewRouter()
+	r.Use(telemetry.Middleware)
+	r.Get("/api/v1/courses/{id}", func(w http.ResponseWriter, r *http.Request) {
+		web.GetLogger(r.Context()).Info("handling")
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/courses/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := context.WithValue(req.Context(), web.LoggerCtxKey, logger)
+	req = req.WithContext(web.WithLoggerAttrs(ctx, "request_id", "req-1"))
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+
+	t.Run("should name the span after the route template", func(t *testing.T) {
+		assert.Equal(t, "GET /api/v1/courses/{id}", span.Name())
+	})
+
+	t.Run("should continue the incoming trace", func(t *testing.T) {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	})
+
+	t.Run("should mark server errors", func(t *testing.T) {
+		assert.Equal(t, codes.Error, span.Status().Code)
+	})
+
+	t.Run("should add trace and span ids to the request logger", func(t *testing.T) {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+		assert.Equal(t, "req-1", entry["request_id"])
+		assert.Equal(t, span.SpanContext().TraceID().String(), entry["trace_id"])
+		assert.Equal(t, span.SpanContext().SpanID().String(), entry["span_id"])
+	})
+}
@@ -0,0 +1,119 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/marcelofabianov/fault"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/marcelofabianov/dojo-go/config"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+// Provider owns the global tracer provider and whatever its exporter writes
+// to, so both are released on Shutdown.
+type Provider struct {
+	tracerProvider *sdktrace.TracerProvider
+	file           io.Closer
+}
+
+// NewProvider installs the global tracer provider and the W3C trace context
+// and baggage propagators. Packages get their tracer from otel.Tracer, which
+// follows the global provider.
+func NewProvider(cfg *config.TracingConfig) (*Provider, error) {
+	p := &Provider{}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	}
+
+	res, err := resource.New(context.Background(),
+		resource.WithAttributes(semconv.ServiceName(cfg.ServiceName)),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fault.Wrap(err, "failed to build tracing resource", fault.WithCode(fault.Internal))
+	}
+	opts = append(opts, sdktrace.WithResource(res))
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case ExporterNone, "":
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		var file *os.File
+		file, err = os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err == nil {
+			p.file = file
+			exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+		}
+	case ExporterOTLP:
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(context.Background(), clientOpts...)
+	default:
+		return nil, fault.New("unknown tracing exporter, must be none, stdout, file or otlp",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("exporter", cfg.Exporter),
+		)
+	}
+	if err != nil {
+		return nil, fault.Wrap(err, "failed to create tracing exporter",
+			fault.WithCode(fault.Internal),
+			fault.WithContext("exporter", cfg.Exporter),
+		)
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	p.tracerProvider = sdktrace.NewTracerProvider(opts...)
+
+	otel.SetTracerProvider(p.tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return p, nil
+}
+
+// Shutdown flushes pending spans.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	err := p.tracerProvider.Shutdown(ctx)
+	if p.file != nil {
+		if cerr := p.file.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// End records err on span, if any, and ends it. Use it deferred with a named
+// error result.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,164 @@
+package tenant
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/httprate"
+	"github.com/marcelofabianov/fault"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/auth"
+	"github.com/marcelofabianov/dojo-go/pkg/metrics"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+type Middleware struct {
+	header     string
+	baseDomain string
+	defaultID  string
+	limiter    func(http.Handler) http.Handler
+}
+
+func NewMiddleware(cfg *config.ServerConfig, metrics *metrics.Metrics) *Middleware {
+	m := &Middleware{
+		header:     cfg.Tenancy.Header,
+		baseDomain: strings.ToLower(strings.TrimPrefix(cfg.Tenancy.BaseDomain, ".")),
+		defaultID:  cfg.Tenancy.Default,
+	}
+
+	if cfg.Tenancy.RateLimit > 0 {
+		m.limiter = httprate.Limit(
+			cfg.Tenancy.RateLimit,
+			1*time.Minute,
+			httprate.WithKeyFuncs(keyByTenant),
+			httprate.WithResponseHeaders(httprate.ResponseHeaders{
+				Limit:     "X-Tenant-RateLimit-Limit",
+				Remaining: "X-Tenant-RateLimit-Remaining",
+				Reset:     "X-Tenant-RateLimit-Reset",
+			}),
+			httprate.WithLimitHandler(metrics.LimitHandler("tenant")),
+		)
+	}
+
+	return m
+}
+
+// Handler resolves the tenant of the request and stores it in the context,
+// adding it to the request logger. A tenant bound to the credentials (the
+// API key's tenant or the JWT tenant claim) always wins; the header and the
+// subdomain may only repeat it. Anonymous requests and multi-tenant tokens
+// use the header, then the subdomain of the base domain, then the default
+// tenant. Other credentials without a tenant are Forbidden.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := web.GetLogger(r.Context())
+
+		id, err := m.resolve(r)
+		if err != nil {
+			logger.Warn("tenant resolution failed", "error", err)
+			web.Error(w, r, err)
+			return
+		}
+
+		ctx := WithID(r.Context(), id)
+		ctx = web.WithLoggerAttrs(ctx, "tenant", id)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RateLimit applies the per tenant request limit. It must run after Handler.
+func (m *Middleware) RateLimit(next http.Handler) http.Handler {
+	if m.limiter == nil {
+		return next
+	}
+	return m.limiter(next)
+}
+
+func (m *Middleware) resolve(r *http.Request) (string, error) {
+	requested := strings.ToLower(strings.TrimSpace(r.Header.Get(m.header)))
+	if requested == "" {
+		requested = m.subdomain(r.Host)
+	}
+
+	id, err := Resolve(r.Context(), requested, m.defaultID)
+	if err != nil {
+		return "", err
+	}
+	if id == "" {
+		return "", fault.New("missing tenant",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("header", m.header),
+		)
+	}
+	return id, nil
+}
+
+// Resolve picks the tenant of a call from the tenant bound to the credentials
+// in ctx (the API key's tenant or the JWT tenant claim), the tenant the
+// caller requested and defaultID, in that order. A requested tenant that
+// differs from the bound one is Forbidden, and so are authenticated calls
+// whose credentials are bound to no tenant: only anonymous calls and tokens
+// with the AnyTenant claim may choose one. It returns "" when none is set.
+func Resolve(ctx context.Context, requested, defaultID string) (string, error) {
+	bound := credentialTenant(ctx)
+
+	switch {
+	case bound == "" && auth.IsAuthenticated(ctx):
+		return "", fault.New("credentials are not bound to a tenant",
+			fault.WithCode(fault.Forbidden),
+		)
+	case bound == AnyTenant:
+		bound = ""
+	}
+
+	switch {
+	case bound != "" && requested != "" && requested != bound:
+		return "", fault.New("tenant does not match the credentials",
+			fault.WithCode(fault.Forbidden),
+			fault.WithContext("tenant", requested),
+		)
+	case bound != "":
+		return bound, nil
+	case requested != "":
+		return requested, Validate(requested)
+	default:
+		return defaultID, nil
+	}
+}
+
+func (m *Middleware) subdomain(host string) string {
+	if m.baseDomain == "" {
+		return ""
+	}
+
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+
+	sub, ok := strings.CutSuffix(host, "."+m.baseDomain)
+	if !ok || strings.Contains(sub, ".") {
+		return ""
+	}
+	return sub
+}
+
+func credentialTenant(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok {
+		return id
+	}
+	if claims, ok := auth.ClaimsFromContext(ctx); ok {
+		return claims.Tenant
+	}
+	return ""
+}
+
+func keyByTenant(r *http.Request) (string, error) {
+	id, _ := FromContext(r.Context())
+	return id, nil
+}
@@ -0,0 +1,103 @@
+//go:build unit
+
+package tenant_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/auth"
+	"github.com/marcelofabianov/dojo-go/pkg/metrics"
+	"github.com/marcelofabianov/dojo-go/pkg/tenant"
+)
+
+func newTenantHandler(tenancy config.TenancyConfig) (http.Handler, *string) {
+	if tenancy.Header == "" {
+		tenancy.Header = "X-Tenant-ID"
+	}
+	m := tenant.NewMiddleware(&config.ServerConfig{Tenancy: tenancy}, metrics.New())
+
+	var got string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = tenant.FromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return m.Handler(m.RateLimit(next)), &got
+}
+
+func TestMiddleware_Handler(t *testing.T) {
+	tests := []struct {
+		name       string
+		tenancy    config.TenancyConfig
+		host       string
+		header     string
+		bound      string
+		token      bool
+		claim      string
+		wantStatus int
+		wantTenant string
+	}{
+		{name: "header", header: "school-a", wantStatus: http.StatusOK, wantTenant: "school-a"},
+		{name: "subdomain", tenancy: config.TenancyConfig{BaseDomain: "dojo.test"}, host: "school-b.dojo.test:8080", wantStatus: http.StatusOK, wantTenant: "school-b"},
+		{name: "header before subdomain", tenancy: config.TenancyConfig{BaseDomain: "dojo.test"}, host: "school-b.dojo.test", header: "school-a", wantStatus: http.StatusOK, wantTenant: "school-a"},
+		{name: "nested subdomain is ignored", tenancy: config.TenancyConfig{BaseDomain: "dojo.test", Default: "default"}, host: "a.b.dojo.test", wantStatus: http.StatusOK, wantTenant: "default"},
+		{name: "default tenant", tenancy: config.TenancyConfig{Default: "default"}, wantStatus: http.StatusOK, wantTenant: "default"},
+		{name: "token claim", claim: "school-a", wantStatus: http.StatusOK, wantTenant: "school-a"},
+		{name: "token claim repeated in header", claim: "school-a", header: "school-a", wantStatus: http.StatusOK, wantTenant: "school-a"},
+		{name: "header overriding token claim", claim: "school-a", header: "school-b", wantStatus: http.StatusForbidden},
+		{name: "header overriding api key tenant", bound: "school-a", header: "school-b", wantStatus: http.StatusForbidden},
+		{name: "token without tenant claim", token: true, header: "school-a", wantStatus: http.StatusForbidden},
+		{name: "token without tenant claim on default tenant", tenancy: config.TenancyConfig{Default: "default"}, token: true, wantStatus: http.StatusForbidden},
+		{name: "multi-tenant token picks header", claim: tenant.AnyTenant, header: "school-b", wantStatus: http.StatusOK, wantTenant: "school-b"},
+		{name: "multi-tenant token on default tenant", tenancy: config.TenancyConfig{Default: "default"}, claim: tenant.AnyTenant, wantStatus: http.StatusOK, wantTenant: "default"},
+		{name: "invalid tenant id", header: "School_A", wantStatus: http.StatusBadRequest},
+		{name: "missing tenant without default", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, got := newTenantHandler(tt.tenancy)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/courses/id", nil)
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+			if tt.bound != "" {
+				req = req.WithContext(tenant.WithID(req.Context(), tt.bound))
+			}
+			if tt.token || tt.claim != "" {
+				req = req.WithContext(auth.WithClaims(req.Context(), &auth.Claims{Tenant: tt.claim}))
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantTenant, *got)
+		})
+	}
+}
+
+func TestMiddleware_RateLimit(t *testing.T) {
+	h, _ := newTenantHandler(config.TenancyConfig{RateLimit: 2})
+
+	do := func(tenantID string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/courses/id", nil)
+		req.Header.Set("X-Tenant-ID", tenantID)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, do("school-a"))
+	assert.Equal(t, http.StatusOK, do("school-a"))
+	assert.Equal(t, http.StatusTooManyRequests, do("school-a"))
+	assert.Equal(t, http.StatusOK, do("school-b"), "limits are tracked per tenant")
+}
@@ -0,0 +1,50 @@
+package tenant
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/marcelofabianov/fault"
+)
+
+type contextKey string
+
+const tenantCtxKey = contextKey("tenant")
+
+// AnyTenant is the tenant claim of tokens that may act on every tenant, such
+// as operators'. They pick the tenant like anonymous requests do.
+const AnyTenant = "*"
+
+// idPattern accepts DNS labels so a tenant id can always be used as a
+// subdomain.
+var idPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+func Validate(id string) error {
+	if !idPattern.MatchString(id) {
+		return fault.New("invalid tenant id",
+			fault.WithCode(fault.Invalid),
+			fault.WithContext("tenant", id),
+		)
+	}
+	return nil
+}
+
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantCtxKey, id)
+}
+
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantCtxKey).(string)
+	return id, ok && id != ""
+}
+
+// Require returns the tenant bound to ctx. Repositories call it before every
+// query, so a code path that forgot to resolve the tenant fails instead of
+// reading across tenants.
+func Require(ctx context.Context) (string, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return "", fault.New("missing tenant in context", fault.WithCode(fault.Internal))
+	}
+	return id, nil
+}
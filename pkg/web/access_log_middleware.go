@@ -0,0 +1,85 @@
+package web
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/marcelofabianov/dojo-go/config"
+)
+
+// AccessLogMiddleware logs one entry per request with the request logger, so
+// entries carry the request id and trace ids. It must run after
+// SlogLoggerMiddleware. Successful responses are sampled, everything else is
+// always logged; requests slower than the threshold are logged as warnings.
+func AccessLogMiddleware(cfg config.AccessLogConfig) func(http.Handler) http.Handler {
+	excluded := make(map[string]struct{}, len(cfg.ExcludePaths))
+	for _, path := range cfg.ExcludePaths {
+		excluded[path] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := excluded[r.URL.Path]; ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			duration := time.Since(start)
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			slow := cfg.SlowThreshold > 0 && duration > cfg.SlowThreshold && !isStream(r)
+			success := status >= http.StatusOK && status < http.StatusMultipleChoices
+			if success && !slow && rand.Float64() >= cfg.SuccessSampleRate {
+				return
+			}
+
+			route := ""
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				route = rctx.RoutePattern()
+			}
+
+			level, msg := slog.LevelInfo, "http request"
+			switch {
+			case status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case slow:
+				level, msg = slog.LevelWarn, "slow http request"
+			}
+
+			GetLogger(r.Context()).LogAttrs(r.Context(), level, msg,
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("route", route),
+				slog.Int("status", status),
+				slog.Int("bytes", ww.BytesWritten()),
+				slog.Duration("duration", duration),
+				slog.String("remote_ip", r.RemoteAddr),
+				slog.String("user_agent", r.UserAgent()),
+			)
+		})
+	}
+}
+
+// isStream reports whether r opens a long-lived stream (SSE or WebSocket),
+// whose duration says nothing about the server's latency.
+func isStream(r *http.Request) bool {
+	return r.Header.Get("Upgrade") != "" || strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
@@ -0,0 +1,109 @@
+//go:build unit
+
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+func serveAccessLog(t *testing.T, cfg config.AccessLogConfig, path string, status int, delay time.Duration) []map[string]any {
+	t.Helper()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	r := chi.NewRouter()
+	r.Use(web.AccessLogMiddleware(cfg))
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(delay)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("hello"))
+	}
+	r.Get("/api/v1/courses/{id}", handler)
+	r.Get("/healthz", handler)
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("User-Agent", "curl/8.0")
+	req = req.WithContext(context.WithValue(req.Context(), web.LoggerCtxKey, logger.With("request_id", "req-1")))
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	cfg := config.AccessLogConfig{
+		Enabled:           true,
+		SuccessSampleRate: 1,
+		ExcludePaths:      []string{"/healthz"},
+		SlowThreshold:     time.Second,
+	}
+
+	t.Run("should log the request with its route and request id", func(t *testing.T) {
+		entries := serveAccessLog(t, cfg, "/api/v1/courses/42", http.StatusOK, 0)
+
+		require.Len(t, entries, 1)
+		entry := entries[0]
+		assert.Equal(t, "INFO", entry["level"])
+		assert.Equal(t, "GET", entry["method"])
+		assert.Equal(t, "/api/v1/courses/{id}", entry["route"])
+		assert.Equal(t, float64(200), entry["status"])
+		assert.Equal(t, float64(5), entry["bytes"])
+		assert.Equal(t, "curl/8.0", entry["user_agent"])
+		assert.Equal(t, "req-1", entry["request_id"])
+		assert.NotEmpty(t, entry["remote_ip"])
+	})
+
+	t.Run("should skip excluded paths", func(t *testing.T) {
+		assert.Empty(t, serveAccessLog(t, cfg, "/healthz", http.StatusOK, 0))
+	})
+
+	t.Run("should sample successful responses only", func(t *testing.T) {
+		sampled := cfg
+		sampled.SuccessSampleRate = 0
+
+		assert.Empty(t, serveAccessLog(t, sampled, "/api/v1/courses/42", http.StatusOK, 0))
+
+		entries := serveAccessLog(t, sampled, "/api/v1/courses/42", http.StatusInternalServerError, 0)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "ERROR", entries[0]["level"])
+	})
+
+	t.Run("should warn about slow requests even when sampled out", func(t *testing.T) {
+		slow := cfg
+		slow.SuccessSampleRate = 0
+		slow.SlowThreshold = time.Millisecond
+
+		entries := serveAccessLog(t, slow, "/api/v1/courses/42", http.StatusOK, 5*time.Millisecond)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "WARN", entries[0]["level"])
+		assert.Equal(t, "slow http request", entries[0]["msg"])
+	})
+
+	t.Run("should do nothing when disabled", func(t *testing.T) {
+		assert.Empty(t, serveAccessLog(t, config.AccessLogConfig{}, "/api/v1/courses/42", http.StatusInternalServerError, 0))
+	})
+}
@@ -13,6 +13,9 @@ import (
 	"github.com/go-chi/httprate"
 
 	"github.com/marcelofabianov/dojo-go/config"
+	"github.com/marcelofabianov/dojo-go/pkg/metrics"
+	"github.com/marcelofabianov/dojo-go/pkg/shutdown"
+	"github.com/marcelofabianov/dojo-go/pkg/telemetry"
 )
 
 func NewServer(cfg *config.Config, logger *slog.Logger, router *chi.Mux) *http.Server {
@@ -25,20 +28,26 @@ func NewServer(cfg *config.Config, logger *slog.Logger, router *chi.Mux) *http.S
 	}
 }
 
-func NewRouter(cfg *config.ServerConfig, logger *slog.Logger) *chi.Mux {
+func NewRouter(cfg *config.ServerConfig, logger *slog.Logger, m *metrics.Metrics, coordinator *shutdown.Coordinator) *chi.Mux {
 	r := chi.NewMux()
 
+	r.Use(coordinator.Middleware)
+	if cfg.Metrics.Enabled {
+		r.Use(m.Middleware)
+	}
+	r.Use(telemetry.Middleware)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
 	r.Use(SlogLoggerMiddleware(logger))
+	r.Use(AccessLogMiddleware(cfg.AccessLog))
 	r.Use(httprate.Limit(
 		cfg.API.RateLimit,
 		1*time.Minute,
 		httprate.WithKeyFuncs(httprate.KeyByIP, httprate.KeyByEndpoint),
 		httprate.WithResponseHeaders(headersRateLimit()),
+		httprate.WithLimitHandler(m.LimitHandler("ip")),
 	))
-	r.Use(middleware.Heartbeat("/ping"))
 	r.Use(cors.Handler(setCorsOptions(cfg.CORS)))
 	r.Use(apiSecurityHeaders(cfg))
 
@@ -94,15 +103,6 @@ func setCorsOptions(cfg config.CORSConfig) cors.Options {
 	}
 }
 
-func HealthCheckHandler(w http.ResponseWriter, r *http.Request) {
-	status := struct {
-		Status string `json:"status"`
-	}{
-		Status: "OK",
-	}
-	Success(w, r, http.StatusOK, status)
-}
-
 func IndexHandler(w http.ResponseWriter, r *http.Request) {
 	Success(w, r, http.StatusOK, nil)
 }
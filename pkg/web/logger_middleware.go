@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type contextKey string
@@ -26,10 +27,35 @@ func SlogLoggerMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 	}
 }
 
+// WithLoggerAttrs adds attributes to the request logger stored in ctx.
+func WithLoggerAttrs(ctx context.Context, args ...any) context.Context {
+	logger, ok := ctx.Value(LoggerCtxKey).(*slog.Logger)
+	if !ok {
+		logger = slog.Default()
+	}
+	return context.WithValue(ctx, LoggerCtxKey, logger.With(args...))
+}
+
+// GetLogger returns the request logger, tagged with the trace and span ids of
+// the span active in ctx.
 func GetLogger(ctx context.Context) *slog.Logger {
 	logger, ok := ctx.Value(LoggerCtxKey).(*slog.Logger)
 	if !ok {
-		return slog.Default()
+		logger = slog.Default()
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		logger = logger.With("trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
 	}
 	return logger
 }
+
+// WithRequestID stores a request id for transports that do not go through
+// chi's RequestID middleware, such as gRPC.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, middleware.RequestIDKey, requestID)
+}
+
+func GetRequestID(ctx context.Context) string {
+	return middleware.GetReqID(ctx)
+}
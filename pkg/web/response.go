@@ -2,20 +2,63 @@ package web
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/marcelofabianov/fault"
 )
 
+// Fault codes for work that did not finish in time or could not start,
+// which fault has no code for. Adapters give them to the errors of their
+// dependencies, e.g. a database that timed out or is shutting down.
+const (
+	Timeout     fault.Code = "timeout"
+	Unavailable fault.Code = "unavailable"
+)
+
+// statusCodes holds the codes fault does not know about.
+var statusCodes = map[fault.Code]int{
+	Timeout:     http.StatusGatewayTimeout,
+	Unavailable: http.StatusServiceUnavailable,
+}
+
 func Success(w http.ResponseWriter, r *http.Request, status int, data any) {
 	writeJSON(w, status, data)
 }
 
 func Error(w http.ResponseWriter, r *http.Request, err error) {
-	response := fault.ToResponse(err)
+	response := ToResponse(err)
 	writeJSON(w, response.StatusCode, response)
 }
 
+// ToResponse is fault.ToResponse for the errors of this API: a Timeout or
+// Unavailable fault anywhere in err is answered with 504 or 503, even when
+// it was wrapped again, e.g. as Internal by a repository.
+func ToResponse(err error) fault.ErrorResponse {
+	if cause, ok := transient(err); ok {
+		err = cause
+	}
+
+	response := fault.ToResponse(err)
+	if status, ok := statusCodes[fault.Code(response.Code)]; ok {
+		response.StatusCode = status
+	}
+	return response
+}
+
+// transient returns the outermost fault of err with one of statusCodes.
+func transient(err error) (*fault.Error, bool) {
+	for err != nil {
+		if fErr, ok := err.(*fault.Error); ok {
+			if _, ok := statusCodes[fErr.Code]; ok {
+				return fErr, true
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil, false
+}
+
 func writeJSON(w http.ResponseWriter, status int, data any) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(status)
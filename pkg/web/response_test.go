@@ -0,0 +1,41 @@
+//go:build unit
+
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/marcelofabianov/fault"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/marcelofabianov/dojo-go/pkg/web"
+)
+
+func TestToResponse(t *testing.T) {
+	internal := func(err error) error {
+		return fault.Wrap(err, "failed to get course by id from database", fault.WithCode(fault.Internal))
+	}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"timeout", internal(fault.Wrap(context.DeadlineExceeded, "the operation timed out", fault.WithCode(web.Timeout))), http.StatusGatewayTimeout, "timeout"},
+		{"unavailable", internal(fault.New("the database is unavailable, try again later", fault.WithCode(web.Unavailable))), http.StatusServiceUnavailable, "unavailable"},
+		{"unclassified deadline", internal(context.DeadlineExceeded), http.StatusInternalServerError, "internal_error"},
+		{"not found", fault.New("course not found", fault.WithCode(fault.NotFound)), http.StatusNotFound, "not_found"},
+		{"internal", internal(context.Canceled), http.StatusInternalServerError, "internal_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := web.ToResponse(tt.err)
+			assert.Equal(t, tt.status, response.StatusCode)
+			assert.Equal(t, tt.code, response.Code)
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -27,15 +28,80 @@ import (
 	"github.com/marcelofabianov/dojo-go/config"
 	"github.com/marcelofabianov/dojo-go/internal/di"
 	"github.com/marcelofabianov/dojo-go/internal/handler"
+	"github.com/marcelofabianov/dojo-go/pkg/db"
 )
 
 var (
 	testServer *httptest.Server
 )
 
+// TestMain runs the suite against a Postgres container, or without Docker
+// when E2E_DB_DRIVER is memory or sqlite.
 func TestMain(m *testing.M) {
 	ctx := context.Background()
 
+	var testDBConfig *config.DBConfig
+	switch os.Getenv("E2E_DB_DRIVER") {
+	case db.DriverMemory:
+		testDBConfig = &config.DBConfig{Driver: db.DriverMemory}
+	case db.DriverSQLite:
+		dir, err := os.MkdirTemp("", "dojo-e2e")
+		if err != nil {
+			log.Fatalf("could not create sqlite dir: %s", err)
+		}
+		defer os.RemoveAll(dir)
+
+		testDBConfig = &config.DBConfig{
+			Driver:       db.DriverSQLite,
+			Path:         filepath.Join(dir, "dojo.db"),
+			MaxOpenConns: 10,
+			MaxIdleConns: 5,
+			QueryTimeout: 5 * time.Second,
+			ExecTimeout:  3 * time.Second,
+			AutoMigrate:  true,
+		}
+	default:
+		var terminate func()
+		testDBConfig, terminate = startPostgres(ctx)
+		defer terminate()
+	}
+
+	var router *chi.Mux
+	app := fx.New(
+		di.Config,
+		di.Pkg,
+		di.Migrate,
+		di.Health,
+		di.Shutdown,
+		di.Metrics,
+		di.Event,
+		di.Idempotency,
+		di.Repository,
+		di.Service,
+		di.Collab,
+		di.Handler,
+		fx.Replace(testDBConfig),
+		fx.Populate(&router),
+	)
+
+	if err := app.Start(ctx); err != nil {
+		log.Fatalf("failed to start fx app: %s", err)
+	}
+
+	testServer = httptest.NewServer(router)
+
+	defer func() {
+		testServer.Close()
+		if err := app.Stop(ctx); err != nil {
+			log.Printf("failed to stop fx app: %s", err)
+		}
+	}()
+
+	exitCode := m.Run()
+	os.Exit(exitCode)
+}
+
+func startPostgres(ctx context.Context) (*config.DBConfig, func()) {
 	pgContainer, err := postgres.RunContainer(ctx,
 		testcontainers.WithImage("postgres:16-alpine"),
 		postgres.WithDatabase("test-db-e2e"),
@@ -51,16 +117,11 @@ func TestMain(m *testing.M) {
 		log.Fatalf("could not start postgres container: %s", err)
 	}
 
-	defer func() {
-		if err := pgContainer.Terminate(ctx); err != nil {
-			log.Fatalf("could not stop postgres container: %s", err)
-		}
-	}()
-
 	host, _ := pgContainer.Host(ctx)
 	port, _ := pgContainer.MappedPort(ctx, "5432")
 
 	testDBConfig := &config.DBConfig{
+		Driver:          db.DriverPostgres,
 		Host:            host,
 		Port:            port.Int(),
 		User:            "user-e2e",
@@ -86,32 +147,11 @@ func TestMain(m *testing.M) {
 	}
 	tempDB.Close()
 
-	var router *chi.Mux
-	app := fx.New(
-		di.Config,
-		di.Pkg,
-		di.Repository,
-		di.Service,
-		di.Handler,
-		fx.Replace(testDBConfig),
-		fx.Populate(&router),
-	)
-
-	if err := app.Start(ctx); err != nil {
-		log.Fatalf("failed to start fx app: %s", err)
-	}
-
-	testServer = httptest.NewServer(router)
-
-	defer func() {
-		testServer.Close()
-		if err := app.Stop(ctx); err != nil {
-			log.Printf("failed to stop fx app: %s", err)
+	return testDBConfig, func() {
+		if err := pgContainer.Terminate(ctx); err != nil {
+			log.Fatalf("could not stop postgres container: %s", err)
 		}
-	}()
-
-	exitCode := m.Run()
-	os.Exit(exitCode)
+	}
 }
 
 func TestCourseAPI_E2E(t *testing.T) {